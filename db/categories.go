@@ -0,0 +1,103 @@
+package db
+
+import (
+	"database/sql"
+	"txeo-tools-library/models"
+)
+
+// SQLiteCategories loads the category metadata from the categories table created by InitDB
+type SQLiteCategories struct {
+	DB *sql.DB
+}
+
+func (s SQLiteCategories) LoadCategories() (models.Categories, error) {
+	rows, err := s.DB.Query(`SELECT id, name, short_name, deleted, traduction, icon, color, subcategory FROM categories ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var categories models.Categories
+	for rows.Next() {
+		var category models.Category
+		var shortName, icon, color, subcategory sql.NullString
+		var deleted sql.NullBool
+		if err := rows.Scan(&category.ID, &category.Name, &shortName, &deleted, &category.Traduction, &icon, &color, &subcategory); err != nil {
+			return nil, err
+		}
+		category.ShortName = shortName.String
+		category.Deleted = deleted.Bool
+		category.Icon = icon.String
+		category.Color = color.String
+		category.Subcategory = subcategory.String
+		categories = append(categories, category)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+// LoadConcepts retrieves the concepts with their tags
+func LoadConcepts(db *sql.DB) (models.Concepts, error) {
+	rows, err := db.Query(`SELECT id, category_id, name, short_name, icon FROM concepts ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var concepts models.Concepts
+	index := map[int]int{}
+	for rows.Next() {
+		var concept models.Concept
+		var shortName, icon sql.NullString
+		if err := rows.Scan(&concept.ID, &concept.CategoryID, &concept.Name, &shortName, &icon); err != nil {
+			return nil, err
+		}
+		concept.ShortName = shortName.String
+		concept.Icon = icon.String
+		index[concept.ID] = len(concepts)
+		concepts = append(concepts, concept)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	tagRows, err := db.Query(`SELECT id, concept_id, name, slug FROM tags ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer tagRows.Close()
+
+	for tagRows.Next() {
+		var tag models.Tag
+		var name, slug sql.NullString
+		if err := tagRows.Scan(&tag.ID, &tag.ConceptID, &name, &slug); err != nil {
+			return nil, err
+		}
+		tag.Name = name.String
+		tag.Slug = slug.String
+		if i, ok := index[tag.ConceptID]; ok {
+			concepts[i].Tags = append(concepts[i].Tags, tag)
+		}
+	}
+	if err := tagRows.Err(); err != nil {
+		return nil, err
+	}
+
+	return concepts, nil
+}
+
+// LoadCategoryTree builds the category/subcategory/concept tree stored in the database
+func LoadCategoryTree(db *sql.DB) (*models.CategoryTree, error) {
+	categories, err := SQLiteCategories{DB: db}.LoadCategories()
+	if err != nil {
+		return nil, err
+	}
+	concepts, err := LoadConcepts(db)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewCategoryTree(categories, concepts), nil
+}
@@ -0,0 +1,289 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"txeo-tools-library/process"
+	ttrello "txeo-tools-library/trello"
+
+	"github.com/adlio/trello"
+)
+
+// TrelloSyncOptions tunes SyncTrelloBoard
+type TrelloSyncOptions struct {
+	Fields ttrello.FieldNames // ttrello.DefaultFieldNames when empty
+	Full   bool               // Ignore the sync cursor and rewrite every card
+}
+
+// TrelloSync summarizes a SyncTrelloBoard run
+type TrelloSync struct {
+	BoardID   string
+	Lists     int
+	Labels    int
+	Cards     int // Open cards found in the board
+	Updated   int // Cards inserted or updated
+	Unchanged int // Cards skipped as they didn't change since the last sync
+	Closed    int // Cards no longer open in the board
+	Cursor    time.Time
+	Warnings  []string
+}
+
+// SyncTrelloBoard copies a board into the Trello tables: board, lists, labels,
+// and the cards with their labels, custom field values, category and hours.
+// Every open card of the board is still downloaded, as the cards missing from
+// it are closed; the cursor only saves the writes: only the cards whose
+// dateLastActivity is newer than the board cursor (or that were never synced)
+// are written.
+func SyncTrelloBoard(db *sql.DB, src ttrello.Source, boardID string, options TrelloSyncOptions) (TrelloSync, error) {
+	result := TrelloSync{BoardID: boardID}
+	if options.Fields == (ttrello.FieldNames{}) {
+		options.Fields = ttrello.DefaultFieldNames
+	}
+	if err := InitTrelloTables(db); err != nil {
+		return result, err
+	}
+
+	board, err := src.GetBoard(boardID)
+	if err != nil {
+		return result, fmt.Errorf("error fetching board %s: %w", boardID, err)
+	}
+	lists, err := src.GetLists(boardID)
+	if err != nil {
+		return result, fmt.Errorf("error fetching lists of board %s: %w", boardID, err)
+	}
+	labels, err := src.GetLabels(boardID)
+	if err != nil {
+		return result, fmt.Errorf("error fetching labels of board %s: %w", boardID, err)
+	}
+	definitions, err := src.GetCustomFields(boardID)
+	if err != nil {
+		return result, fmt.Errorf("error fetching custom fields of board %s: %w", boardID, err)
+	}
+	cards, err := src.GetBoardCards(boardID)
+	if err != nil {
+		return result, fmt.Errorf("error fetching cards of board %s: %w", boardID, err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return result, err
+	}
+	defer tx.Rollback()
+
+	cursor, err := getSyncCursor(tx, boardID)
+	if err != nil {
+		return result, err
+	}
+	if options.Full {
+		cursor = time.Time{}
+	}
+	synced, err := getSyncedCards(tx, boardID)
+	if err != nil {
+		return result, err
+	}
+
+	if err := upsertTrelloBoard(tx, board); err != nil {
+		return result, err
+	}
+	for _, list := range lists {
+		if err := upsertTrelloList(tx, boardID, list); err != nil {
+			return result, err
+		}
+		result.Lists++
+	}
+	for _, label := range labels {
+		if _, err := tx.Exec(`INSERT INTO trello_labels (id, board_id, name, color) VALUES (?, ?, ?, ?)
+			ON CONFLICT (id) DO UPDATE SET board_id = excluded.board_id, name = excluded.name, color = excluded.color`,
+			label.ID, boardID, label.Name, label.Color); err != nil {
+			return result, fmt.Errorf("error saving label %s: %w", label.ID, err)
+		}
+		result.Labels++
+	}
+
+	result.Cursor = cursor
+	open := map[string]bool{}
+	for _, card := range cards {
+		result.Cards++
+		open[card.ID] = true
+		activity := cardActivity(card)
+		if activity.After(result.Cursor) {
+			result.Cursor = activity
+		}
+		if synced[card.ID] && !activity.After(cursor) {
+			result.Unchanged++
+			continue
+		}
+
+		task, warnings := process.GetTaskFromCard(card, definitions, options.Fields.Hours)
+		result.Warnings = append(result.Warnings, warnings...)
+		if err := upsertTrelloCard(tx, boardID, card, task.Name, task.Category, task.TimeForTask, activity); err != nil {
+			return result, err
+		}
+		if err := replaceCardLabels(tx, card); err != nil {
+			return result, err
+		}
+		if err := replaceCardFields(tx, card, definitions); err != nil {
+			return result, err
+		}
+		result.Updated++
+	}
+
+	// Cards archived, deleted or moved to another board since the last sync
+	for cardID := range synced {
+		if open[cardID] {
+			continue
+		}
+		if _, err := tx.Exec(`UPDATE trello_cards SET closed = 1 WHERE id = ? AND closed = 0`, cardID); err != nil {
+			return result, fmt.Errorf("error closing card %s: %w", cardID, err)
+		}
+		result.Closed++
+	}
+
+	if _, err := tx.Exec(`INSERT INTO trello_sync_cursors (board_id, last_activity, synced_at) VALUES (?, ?, ?)
+		ON CONFLICT (board_id) DO UPDATE SET last_activity = excluded.last_activity, synced_at = excluded.synced_at`,
+		boardID, formatSyncTime(result.Cursor), formatSyncTime(time.Now())); err != nil {
+		return result, fmt.Errorf("error saving sync cursor of board %s: %w", boardID, err)
+	}
+	return result, tx.Commit()
+}
+
+// GetTrelloSyncCursor returns the latest card activity synced for a board, zero when never synced
+func GetTrelloSyncCursor(db *sql.DB, boardID string) (time.Time, error) {
+	if err := InitTrelloTables(db); err != nil {
+		return time.Time{}, err
+	}
+	return getSyncCursor(db, boardID)
+}
+
+/* ╭──────────────────────────────────────────╮ */
+/* │              AUX FUNCTIONS               │ */
+/* ╰──────────────────────────────────────────╯ */
+type queryer interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+func getSyncCursor(q queryer, boardID string) (time.Time, error) {
+	var lastActivity string
+	err := q.QueryRow(`SELECT last_activity FROM trello_sync_cursors WHERE board_id = ?`, boardID).Scan(&lastActivity)
+	if err == sql.ErrNoRows {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(time.RFC3339Nano, lastActivity)
+}
+
+// getSyncedCards returns the open cards of a board written by a previous sync
+func getSyncedCards(tx *sql.Tx, boardID string) (map[string]bool, error) {
+	rows, err := tx.Query(`SELECT id FROM trello_cards WHERE board_id = ? AND closed = 0 AND date_last_activity IS NOT NULL`, boardID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	synced := map[string]bool{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		synced[id] = true
+	}
+	return synced, rows.Err()
+}
+
+func upsertTrelloBoard(tx *sql.Tx, board *trello.Board) error {
+	_, err := tx.Exec(`INSERT INTO trello_boards (id, name, closed, url) VALUES (?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET name = excluded.name, closed = excluded.closed, url = excluded.url`,
+		board.ID, board.Name, board.Closed, board.URL)
+	if err != nil {
+		return fmt.Errorf("error saving board %s: %w", board.ID, err)
+	}
+	return nil
+}
+
+func upsertTrelloList(tx *sql.Tx, boardID string, list *trello.List) error {
+	period, _ := ttrello.ParseListPeriod(list.Name)
+	_, err := tx.Exec(`INSERT INTO trello_lists (id, board_id, name, closed, pos, month, year) VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET board_id = excluded.board_id, name = excluded.name, closed = excluded.closed,
+			pos = excluded.pos, month = excluded.month, year = excluded.year`,
+		list.ID, boardID, list.Name, list.Closed, list.Pos, int(period.Month), period.Year)
+	if err != nil {
+		return fmt.Errorf("error saving list %s: %w", list.ID, err)
+	}
+	return nil
+}
+
+func upsertTrelloCard(tx *sql.Tx, boardID string, card *trello.Card, task, category string, hours float64, activity time.Time) error {
+	var due sql.NullString
+	if card.Due != nil {
+		due = sql.NullString{String: card.Due.UTC().Format(time.RFC3339), Valid: true}
+	}
+	_, err := tx.Exec(`INSERT INTO trello_cards
+		(id, board_id, list_id, name, description, due, closed, deleted, pos, task, category, hours, date_last_activity, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, 0, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			board_id = excluded.board_id, list_id = excluded.list_id, name = excluded.name,
+			description = excluded.description, due = excluded.due, closed = excluded.closed, deleted = 0,
+			pos = excluded.pos, task = excluded.task, category = excluded.category, hours = excluded.hours,
+			date_last_activity = excluded.date_last_activity, updated_at = excluded.updated_at`,
+		card.ID, boardID, card.IDList, card.Name, card.Desc, due, card.Closed, card.Pos,
+		task, category, hours, formatSyncTime(activity), formatSyncTime(activity))
+	if err != nil {
+		return fmt.Errorf("error saving card %s: %w", card.ID, err)
+	}
+	return nil
+}
+
+func replaceCardLabels(tx *sql.Tx, card *trello.Card) error {
+	if _, err := tx.Exec(`DELETE FROM trello_card_labels WHERE card_id = ?`, card.ID); err != nil {
+		return fmt.Errorf("error clearing labels of card %s: %w", card.ID, err)
+	}
+	labelIDs := card.IDLabels
+	if len(labelIDs) == 0 {
+		for _, label := range card.Labels {
+			labelIDs = append(labelIDs, label.ID)
+		}
+	}
+	for _, labelID := range labelIDs {
+		if _, err := tx.Exec(`INSERT OR IGNORE INTO trello_card_labels (card_id, label_id) VALUES (?, ?)`, card.ID, labelID); err != nil {
+			return fmt.Errorf("error saving labels of card %s: %w", card.ID, err)
+		}
+	}
+	return nil
+}
+
+func replaceCardFields(tx *sql.Tx, card *trello.Card, definitions []*trello.CustomField) error {
+	if _, err := tx.Exec(`DELETE FROM trello_card_fields WHERE card_id = ?`, card.ID); err != nil {
+		return fmt.Errorf("error clearing custom fields of card %s: %w", card.ID, err)
+	}
+	names := map[string]string{}
+	for _, definition := range definitions {
+		names[definition.Name] = definition.ID
+	}
+	for name, value := range ttrello.DecodeCustomFields(card, definitions) {
+		var date sql.NullString
+		if !value.Date.IsZero() {
+			date = sql.NullString{String: value.Date.UTC().Format(time.RFC3339), Valid: true}
+		}
+		if _, err := tx.Exec(`INSERT INTO trello_card_fields (card_id, field_id, name, type, text, number, date, checked) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			card.ID, names[name], name, value.Type, value.Text, value.Number, date, value.Checked); err != nil {
+			return fmt.Errorf("error saving custom fields of card %s: %w", card.ID, err)
+		}
+	}
+	return nil
+}
+
+func cardActivity(card *trello.Card) time.Time {
+	if card.DateLastActivity != nil {
+		return card.DateLastActivity.UTC()
+	}
+	return time.Time{}
+}
+
+func formatSyncTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
@@ -0,0 +1,163 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	ttrello "txeo-tools-library/trello"
+)
+
+const (
+	syncBoardID    = "617c56690fcb27430e740522"
+	syncBoardCards = "boards/" + syncBoardID + "/cards"
+)
+
+func newSyncDatabase(t *testing.T) *sql.DB {
+	t.Helper()
+	database, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("error opening database: %v", err)
+	}
+	database.SetMaxOpenConns(1)
+	t.Cleanup(func() { database.Close() })
+	return database
+}
+
+// newSyncSource serves the Olympics board of the trello fixtures, returning
+// its cards so tests can change them
+func newSyncSource(t *testing.T) (*ttrello.FakeServer, ttrello.Source, []map[string]interface{}) {
+	t.Helper()
+	fixtures, err := ttrello.LoadFixtures(filepath.Join("..", "trello", "testdata", "fake"))
+	if err != nil {
+		t.Fatalf("error loading fixtures: %v", err)
+	}
+	var cards []map[string]interface{}
+	if err := json.Unmarshal(fixtures[syncBoardCards], &cards); err != nil {
+		t.Fatalf("error decoding cards: %v", err)
+	}
+	fake := ttrello.NewFakeServer(fixtures)
+	t.Cleanup(fake.Close)
+
+	config := fake.Config()
+	config.Boards.CachePath = filepath.Join(t.TempDir(), "boards.json")
+	client, err := ttrello.New(config)
+	if err != nil {
+		t.Fatalf("error creating client: %v", err)
+	}
+	return fake, ttrello.NewAPISource(client.API), cards
+}
+
+func syncBoard(t *testing.T, database *sql.DB, src ttrello.Source) TrelloSync {
+	t.Helper()
+	result, err := SyncTrelloBoard(database, src, syncBoardID, TrelloSyncOptions{})
+	if err != nil {
+		t.Fatalf("error syncing board: %v", err)
+	}
+	return result
+}
+
+func TestSyncTrelloBoard(t *testing.T) {
+	database := newSyncDatabase(t)
+	fake, src, cards := newSyncSource(t)
+
+	result := syncBoard(t, database, src)
+	if result.Lists != 4 || result.Cards != 6 || result.Updated != 6 || result.Unchanged != 0 || result.Closed != 0 {
+		t.Errorf("first sync = %+v, want 4 lists and 6 cards written", result)
+	}
+	lastActivity := time.Date(2024, 11, 14, 15, 20, 0, 0, time.UTC)
+	if cursor, err := GetTrelloSyncCursor(database, syncBoardID); err != nil || !cursor.Equal(lastActivity) {
+		t.Errorf("cursor = %v (err %v), want %v", cursor, err, lastActivity)
+	}
+	store := &TrelloCardStore{DB: database}
+	if card, err := store.GetCard("6710000000000000000000c2"); err != nil || card.Task.Name != "Fix login redirect" || card.Task.TimeForTask != 2 {
+		t.Errorf("card c2 = %+v (err %v), want the 2h task", card.Task, err)
+	}
+	if card, err := store.GetCard("6710000000000000000000c1"); err != nil || card.Task.TimeForTask != 1.5 {
+		t.Errorf("card c1 = %+v (err %v), want the 1.5h of its Horas field", card.Task, err)
+	}
+
+	// Nothing changed in the board
+	result = syncBoard(t, database, src)
+	if result.Updated != 0 || result.Unchanged != 6 || !result.Cursor.Equal(lastActivity) {
+		t.Errorf("second sync = %+v, want every card unchanged", result)
+	}
+
+	// c1 renamed and c5 archived since the last sync
+	var changed []map[string]interface{}
+	for _, card := range cards {
+		switch card["id"] {
+		case "6710000000000000000000c1":
+			card["name"] = "Weekly call with IOC team (1h)"
+			card["dateLastActivity"] = "2024-11-20T08:00:00.000Z"
+		case "6710000000000000000000c5":
+			continue
+		}
+		changed = append(changed, card)
+	}
+	if err := fake.Set(syncBoardCards, changed); err != nil {
+		t.Fatalf("error changing cards: %v", err)
+	}
+
+	result = syncBoard(t, database, src)
+	if result.Cards != 5 || result.Updated != 1 || result.Unchanged != 4 || result.Closed != 1 {
+		t.Errorf("third sync = %+v, want c1 written and c5 closed", result)
+	}
+	if want := time.Date(2024, 11, 20, 8, 0, 0, 0, time.UTC); !result.Cursor.Equal(want) {
+		t.Errorf("cursor = %v, want %v", result.Cursor, want)
+	}
+	if card, err := store.GetCard("6710000000000000000000c1"); err != nil || card.Name != "Weekly call with IOC team (1h)" {
+		t.Errorf("card c1 = %+v (err %v), want it renamed", card, err)
+	}
+	if card, err := store.GetCard("6710000000000000000000c5"); err != nil || !card.Closed {
+		t.Errorf("card c5 = %+v (err %v), want it closed", card, err)
+	}
+
+	// A full sync rewrites every open card
+	result, err := SyncTrelloBoard(database, src, syncBoardID, TrelloSyncOptions{Full: true})
+	if err != nil || result.Updated != 5 || result.Closed != 0 {
+		t.Errorf("full sync = %+v (err %v), want the 5 open cards written", result, err)
+	}
+}
+
+func TestSyncMigratesWebhookTable(t *testing.T) {
+	database := newSyncDatabase(t)
+	_, src, _ := newSyncSource(t)
+
+	// trello_cards as created by the first webhook receiver, with a card it stored
+	if _, err := database.Exec(`CREATE TABLE trello_cards (
+		id             TEXT PRIMARY KEY,
+		board_id       TEXT NOT NULL DEFAULT '',
+		list_id        TEXT NOT NULL DEFAULT '',
+		name           TEXT NOT NULL DEFAULT '',
+		description    TEXT NOT NULL DEFAULT '',
+		due            TEXT,
+		closed         INTEGER NOT NULL DEFAULT 0,
+		deleted        INTEGER NOT NULL DEFAULT 0,
+		task           TEXT NOT NULL DEFAULT '',
+		category       TEXT NOT NULL DEFAULT '',
+		hours          REAL NOT NULL DEFAULT 0,
+		last_action_id TEXT NOT NULL DEFAULT '',
+		updated_at     TEXT NOT NULL
+	)`); err != nil {
+		t.Fatalf("error creating the old table: %v", err)
+	}
+	if _, err := database.Exec(`INSERT INTO trello_cards (id, board_id, name, updated_at) VALUES ('6720a1b2c3d4e5f6010203c9', ?, 'Webhook card', '2024-11-01T00:00:00Z')`, syncBoardID); err != nil {
+		t.Fatalf("error saving the webhook card: %v", err)
+	}
+
+	result := syncBoard(t, database, src)
+	if result.Updated != 6 || result.Closed != 0 {
+		t.Errorf("sync = %+v, want 6 cards written and the webhook card left alone", result)
+	}
+	store := &TrelloCardStore{DB: database}
+	if card, err := store.GetCard("6720a1b2c3d4e5f6010203c9"); err != nil || card.Closed {
+		t.Errorf("webhook card = %+v (err %v), want it kept open", card, err)
+	}
+	// Migrating twice is a no-op
+	if err := InitTrelloTables(database); err != nil {
+		t.Errorf("error initializing the migrated tables: %v", err)
+	}
+}
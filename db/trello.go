@@ -0,0 +1,320 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"txeo-tools-library/models"
+	"txeo-tools-library/process"
+	ttrello "txeo-tools-library/trello"
+)
+
+// Tables of the Trello data, created by InitTrelloTables
+var trelloTables = []string{
+	`CREATE TABLE IF NOT EXISTS trello_boards (
+		id     TEXT PRIMARY KEY,
+		name   TEXT NOT NULL,
+		closed INTEGER NOT NULL DEFAULT 0,
+		url    TEXT NOT NULL DEFAULT ''
+	)`,
+	`CREATE TABLE IF NOT EXISTS trello_lists (
+		id       TEXT PRIMARY KEY,
+		board_id TEXT NOT NULL,
+		name     TEXT NOT NULL,
+		closed   INTEGER NOT NULL DEFAULT 0,
+		pos      REAL NOT NULL DEFAULT 0,
+		month    INTEGER NOT NULL DEFAULT 0, -- Month of month lists, 0 otherwise
+		year     INTEGER NOT NULL DEFAULT 0
+	)`,
+	`CREATE TABLE IF NOT EXISTS trello_cards (
+		id                 TEXT PRIMARY KEY,
+		board_id           TEXT NOT NULL DEFAULT '',
+		list_id            TEXT NOT NULL DEFAULT '',
+		name               TEXT NOT NULL DEFAULT '',
+		description        TEXT NOT NULL DEFAULT '',
+		due                TEXT,
+		closed             INTEGER NOT NULL DEFAULT 0,
+		deleted            INTEGER NOT NULL DEFAULT 0,
+		pos                REAL NOT NULL DEFAULT 0,
+		task               TEXT NOT NULL DEFAULT '',
+		category           TEXT NOT NULL DEFAULT '',
+		hours              REAL NOT NULL DEFAULT 0,
+		date_last_activity TEXT, -- Set by the sync, NULL for cards only known from webhooks
+		last_action_id     TEXT NOT NULL DEFAULT '',
+		updated_at         TEXT NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS trello_labels (
+		id       TEXT PRIMARY KEY,
+		board_id TEXT NOT NULL,
+		name     TEXT NOT NULL DEFAULT '',
+		color    TEXT NOT NULL DEFAULT ''
+	)`,
+	`CREATE TABLE IF NOT EXISTS trello_card_labels (
+		card_id  TEXT NOT NULL,
+		label_id TEXT NOT NULL,
+		PRIMARY KEY (card_id, label_id)
+	)`,
+	`CREATE TABLE IF NOT EXISTS trello_card_fields (
+		card_id  TEXT NOT NULL,
+		field_id TEXT NOT NULL,
+		name     TEXT NOT NULL,
+		type     TEXT NOT NULL,
+		text     TEXT NOT NULL DEFAULT '',
+		number   REAL NOT NULL DEFAULT 0,
+		date     TEXT,
+		checked  INTEGER NOT NULL DEFAULT 0,
+		PRIMARY KEY (card_id, field_id)
+	)`,
+	`CREATE TABLE IF NOT EXISTS trello_sync_cursors (
+		board_id      TEXT PRIMARY KEY,
+		last_activity TEXT NOT NULL, -- Latest dateLastActivity of the cards synced
+		synced_at     TEXT NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS trello_cards_board ON trello_cards (board_id)`,
+	`CREATE INDEX IF NOT EXISTS trello_cards_list ON trello_cards (list_id)`,
+}
+
+// Columns added to tables created by earlier versions, by table
+var trelloColumns = map[string][]struct{ name, definition string }{
+	"trello_cards": {
+		{"pos", "REAL NOT NULL DEFAULT 0"},
+		{"date_last_activity", "TEXT"},
+	},
+}
+
+// InitTrelloTables creates the Trello tables when missing and adds the
+// columns missing in tables created by earlier versions
+func InitTrelloTables(db *sql.DB) error {
+	for _, statement := range trelloTables {
+		if _, err := db.Exec(statement); err != nil {
+			return fmt.Errorf("error creating Trello tables: %w", err)
+		}
+	}
+	for table, columns := range trelloColumns {
+		existing, err := tableColumns(db, table)
+		if err != nil {
+			return fmt.Errorf("error reading columns of %s: %w", table, err)
+		}
+		for _, column := range columns {
+			if existing[column.name] {
+				continue
+			}
+			if _, err := db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column.name, column.definition)); err != nil {
+				return fmt.Errorf("error adding column %s to %s: %w", column.name, table, err)
+			}
+		}
+	}
+	return nil
+}
+
+// TrelloCard is the local copy of a card kept by TrelloCardStore
+type TrelloCard struct {
+	ID           string
+	BoardID      string
+	ListID       string
+	Name         string
+	Description  string
+	Due          *time.Time
+	Closed       bool
+	Deleted      bool
+	Task         models.Task
+	LastActionID string
+	UpdatedAt    time.Time // Date of the last action applied
+}
+
+// TrelloCardStore keeps the trello_cards table up to date with the webhook events
+type TrelloCardStore struct {
+	DB         *sql.DB
+	HoursField string // Custom field holding the hours, as saved by the sync, ttrello.DefaultFieldNames.Hours when empty
+}
+
+// NewTrelloCardStore creates the Trello tables when missing
+func NewTrelloCardStore(db *sql.DB) (*TrelloCardStore, error) {
+	if err := InitTrelloTables(db); err != nil {
+		return nil, err
+	}
+	return &TrelloCardStore{DB: db}, nil
+}
+
+// ApplyCardEvent merges an event into the stored card. Events older than the
+// last one applied are ignored, as Trello doesn't guarantee their order.
+func (s *TrelloCardStore) ApplyCardEvent(event ttrello.CardEvent) error {
+	card, err := s.GetCard(event.CardID)
+	exists := err == nil
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	if exists && event.Date.Before(card.UpdatedAt) {
+		return nil
+	}
+	// The card left this board, unless it already arrived to the target board
+	if event.Type == ttrello.CardRemoved && (!exists || card.BoardID != event.BoardID) {
+		return nil
+	}
+
+	card.ID = event.CardID
+	if event.BoardID != "" && event.Type != ttrello.CardRemoved {
+		card.BoardID = event.BoardID
+	}
+	if event.ListID != "" {
+		card.ListID = event.ListID
+	}
+	// Actions only send the description when it changes, so the task is
+	// recomputed from what is stored when the name or description change
+	recompute := !exists
+	if event.Name != "" && event.Name != card.Name {
+		card.Name = event.Name
+		recompute = true
+	}
+	if event.HasChanged("desc") {
+		card.Description = event.Desc
+		recompute = true
+	}
+	if recompute {
+		if card.Task, err = s.cardTask(card); err != nil {
+			return err
+		}
+	}
+	if event.HasChanged("due") {
+		card.Due = event.Due
+	}
+	switch event.Type {
+	case ttrello.CardCreated, ttrello.CardAdded:
+		card.Closed = event.Closed
+		card.Deleted = false
+	case ttrello.CardArchived, ttrello.CardRestored:
+		card.Closed = event.Closed
+	case ttrello.CardDeleted, ttrello.CardRemoved:
+		card.Deleted = true
+	}
+	card.LastActionID = event.ActionID
+	card.UpdatedAt = event.Date
+
+	return s.saveCard(card)
+}
+
+// GetCard returns a stored card, sql.ErrNoRows when unknown
+func (s *TrelloCardStore) GetCard(id string) (TrelloCard, error) {
+	row := s.DB.QueryRow(`SELECT id, board_id, list_id, name, description, due, closed, deleted, task, category, hours, last_action_id, updated_at FROM trello_cards WHERE id = ?`, id)
+	return scanTrelloCard(row)
+}
+
+// GetCards returns the cards of a list that are neither archived nor deleted
+func (s *TrelloCardStore) GetCards(listID string) ([]TrelloCard, error) {
+	rows, err := s.DB.Query(`SELECT id, board_id, list_id, name, description, due, closed, deleted, task, category, hours, last_action_id, updated_at FROM trello_cards WHERE list_id = ? AND closed = 0 AND deleted = 0 ORDER BY name`, listID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cards []TrelloCard
+	for rows.Next() {
+		card, err := scanTrelloCard(rows)
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, card)
+	}
+	return cards, rows.Err()
+}
+
+/* ╭──────────────────────────────────────────╮ */
+/* │              AUX FUNCTIONS               │ */
+/* ╰──────────────────────────────────────────╯ */
+func (s *TrelloCardStore) saveCard(card TrelloCard) error {
+	var due sql.NullString
+	if card.Due != nil {
+		due = sql.NullString{String: card.Due.UTC().Format(time.RFC3339), Valid: true}
+	}
+	// Upsert, keeping the columns only the sync knows about
+	_, err := s.DB.Exec(`INSERT INTO trello_cards
+		(id, board_id, list_id, name, description, due, closed, deleted, task, category, hours, last_action_id, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			board_id = excluded.board_id, list_id = excluded.list_id, name = excluded.name,
+			description = excluded.description, due = excluded.due, closed = excluded.closed,
+			deleted = excluded.deleted, task = excluded.task, category = excluded.category,
+			hours = excluded.hours, last_action_id = excluded.last_action_id, updated_at = excluded.updated_at`,
+		card.ID, card.BoardID, card.ListID, card.Name, card.Description, due, card.Closed, card.Deleted,
+		card.Task.Name, card.Task.Category, card.Task.TimeForTask, card.LastActionID, card.UpdatedAt.UTC().Format(time.RFC3339Nano))
+	if err != nil {
+		return fmt.Errorf("error saving Trello card %s: %w", card.ID, err)
+	}
+	return nil
+}
+
+// cardTask turns the stored card into a task, taking the hours from the
+// hours custom field saved by the sync before the name and description
+func (s *TrelloCardStore) cardTask(card TrelloCard) (models.Task, error) {
+	hoursField := s.HoursField
+	if hoursField == "" {
+		hoursField = ttrello.DefaultFieldNames.Hours
+	}
+	var fieldType, text string
+	var number float64
+	var fieldValue interface{}
+	err := s.DB.QueryRow(`SELECT type, text, number FROM trello_card_fields WHERE card_id = ? AND name = ? COLLATE NOCASE`,
+		card.ID, hoursField).Scan(&fieldType, &text, &number)
+	switch {
+	case err == nil && fieldType == ttrello.FieldTypeNumber:
+		fieldValue = number
+	case err == nil && text != "":
+		fieldValue = text
+	case err != nil && !errors.Is(err, sql.ErrNoRows):
+		return models.Task{}, fmt.Errorf("error reading custom fields of card %s: %w", card.ID, err)
+	}
+
+	duration := process.GetTaskDuration(card.Name, card.Description, fieldValue)
+	return models.Task{
+		Name:        duration.Name,
+		Category:    process.GetTaskCategory(duration.Name),
+		TimeForTask: duration.Hours,
+	}, nil
+}
+
+// tableColumns returns the names of the columns of a table
+func tableColumns(db *sql.DB, table string) (map[string]bool, error) {
+	rows, err := db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns := map[string]bool{}
+	for rows.Next() {
+		var (
+			cid, notNull, pk int
+			name, kind       string
+			defaultValue     sql.NullString
+		)
+		if err := rows.Scan(&cid, &name, &kind, &notNull, &defaultValue, &pk); err != nil {
+			return nil, err
+		}
+		columns[name] = true
+	}
+	return columns, rows.Err()
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanTrelloCard(row rowScanner) (TrelloCard, error) {
+	var card TrelloCard
+	var due sql.NullString
+	var updatedAt string
+	err := row.Scan(&card.ID, &card.BoardID, &card.ListID, &card.Name, &card.Description, &due, &card.Closed, &card.Deleted,
+		&card.Task.Name, &card.Task.Category, &card.Task.TimeForTask, &card.LastActionID, &updatedAt)
+	if err != nil {
+		return TrelloCard{}, err
+	}
+	if due.Valid {
+		if parsed, err := time.Parse(time.RFC3339, due.String); err == nil {
+			card.Due = &parsed
+		}
+	}
+	card.UpdatedAt, _ = time.Parse(time.RFC3339Nano, updatedAt)
+	return card, nil
+}
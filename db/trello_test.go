@@ -0,0 +1,59 @@
+package db
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	ttrello "txeo-tools-library/trello"
+)
+
+func TestApplyCardEventMovedToBoardKeepsDescAndDue(t *testing.T) {
+	store, err := NewTrelloCardStore(newSyncDatabase(t))
+	if err != nil {
+		t.Fatalf("error creating store: %v", err)
+	}
+	due := time.Date(2024, 10, 3, 17, 0, 0, 0, time.UTC)
+	created := ttrello.CardEvent{
+		Type:     ttrello.CardCreated,
+		ActionID: "6720a1b2c3d4e5f601020301",
+		Date:     time.Date(2024, 10, 1, 9, 0, 0, 0, time.UTC),
+		BoardID:  syncBoardID,
+		ListID:   "6710000000000000000000a2",
+		CardID:   "6720a1b2c3d4e5f6010203c9",
+		Name:     "Weekly call with LIV Golf",
+		Desc:     "Took 3h",
+		Due:      &due,
+	}
+	if err := store.ApplyCardEvent(created); err != nil {
+		t.Fatalf("error applying the creation: %v", err)
+	}
+
+	body, err := os.ReadFile(filepath.Join("..", "trello", "testdata", "webhooks", "move_to_board.json"))
+	if err != nil {
+		t.Fatalf("error reading payload: %v", err)
+	}
+	moved, ok, err := ttrello.ParseWebhookAction(body)
+	if err != nil || !ok || moved.Type != ttrello.CardAdded {
+		t.Fatalf("got event %+v (%v, %v), want a card added to the board", moved, ok, err)
+	}
+	if err := store.ApplyCardEvent(moved); err != nil {
+		t.Fatalf("error applying the move: %v", err)
+	}
+
+	card, err := store.GetCard(created.CardID)
+	if err != nil {
+		t.Fatalf("error reading card: %v", err)
+	}
+	if card.BoardID != "66757258661e38a299a7e687" || card.ListID != "6757000000000000000000a1" {
+		t.Errorf("got board %s and list %s, want the LivGolf ones", card.BoardID, card.ListID)
+	}
+	if card.Description != "Took 3h" || card.Due == nil || !card.Due.Equal(due) {
+		t.Errorf("got description %q and due %v, want them kept from before the move", card.Description, card.Due)
+	}
+	// The move renamed the card with its hours, which win over the description
+	if card.Task.Name != "Weekly call with LIV Golf" || card.Task.TimeForTask != 1 {
+		t.Errorf("got task %q (%.2fh), want the 1h of the new name", card.Task.Name, card.Task.TimeForTask)
+	}
+}
@@ -11,12 +11,15 @@ import (
 
 // Category model adapted for SQLite
 type Category struct {
-	ID         int            // SQLite uses int for primary keys by default
-	Name       string         // The name of the category
-	Count      int            // A count of how many times this category is used
-	Deleted    bool           // Whether the category is marked as deleted
-	Traduction sql.NullString // To handle cases where a translation might be optional or NULL
-	Icon       string
+	ID          int            // SQLite uses int for primary keys by default
+	Name        string         // The name of the category
+	Count       int            // A count of how many times this category is used
+	Deleted     bool           // Whether the category is marked as deleted
+	Traduction  sql.NullString // To handle cases where a translation might be optional or NULL
+	Icon        string
+	ShortName   string // Stable identifier used by the categorizer
+	Color       string // Trello label color (green, yellow, orange, red, purple, blue, sky, lime, pink, black)
+	Subcategory string // Set when the row is a subcategory of the category called Name
 }
 type Categories []Category
 
@@ -44,6 +47,14 @@ func (c Categories) GetCategories() Categories {
 	return categories
 }
 
+// LocalizedName returns the translation of the category when there is one
+func (c Category) LocalizedName() string {
+	if c.Traduction.Valid && c.Traduction.String != "" {
+		return c.Traduction.String
+	}
+	return c.Name
+}
+
 // Print
 func (c Categories) PrintCategories() {
 	for _, category := range categories {
@@ -0,0 +1,28 @@
+package models
+
+// Concept is the deepest level of the category tree, it hangs off a category or subcategory
+type Concept struct {
+	ID         int
+	CategoryID int // Row of the categories table (category or subcategory) this concept belongs to
+	Name       string
+	ShortName  string
+	Icon       string
+	Tags       Tags // Keywords that classify a task or income into this concept
+}
+type Concepts []Concept
+
+// Tag is a keyword of a concept
+type Tag struct {
+	ID        int
+	ConceptID int
+	Name      string
+	Slug      string
+}
+type Tags []Tag
+
+// Classification is the position of a task or income in the category tree
+type Classification struct {
+	Category    string
+	Subcategory string
+	Concept     string
+}
@@ -14,6 +14,8 @@ type Income struct {
 	IVA            float64   // IVA or tax applied
 	Retention      float64   // Any retention or deduction
 	Category       string    // Category of the income
+	Subcategory    string    // Subcategory of the income, if any
+	Concept        string    // Concept of the income, if any
 	PaymentMethod  string    // Payment method (e.g., bank transfer, cash)
 	IncomeSource   string    // Source of the income, such as a client or employer
 	IsRecurring    bool      // Whether the income is recurring or not
@@ -45,6 +45,20 @@ func (months Months) GetMonthByName(monthName string) Month {
 	}
 	return Month{}
 }
+
+// IsAllYear tells whether the month is the "All Year" entry
+func (m Month) IsAllYear() bool {
+	return m.Time == 0 && strings.EqualFold(m.ShortedName, "All")
+}
+
+// Number returns the year, 0 for "All Years" or an empty Year
+func (y Year) Number() int {
+	if y.Time.IsZero() || y.Time.Year() < 1 {
+		return 0
+	}
+	return y.Time.Year()
+}
+
 func (y Year) GetYears() Years {
 	return Years{
 		{Name: "2024", Time: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
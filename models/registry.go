@@ -0,0 +1,188 @@
+package models
+
+import (
+	"database/sql"
+	_ "embed"
+	"encoding/json"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Short names of the built-in categories
+const (
+	CategoryMeetings       = "meetings"
+	CategoryImplementation = "implementation"
+	CategoryDocumentation  = "documentation"
+	CategoryConversations  = "conversations"
+	CategoryOther          = "other"
+)
+
+// DefaultCategoryIcon is used for categories without icon
+const DefaultCategoryIcon = "❓"
+
+//go:embed categories.json
+var embeddedCategories []byte
+
+// CategorySource is anything able to load the category metadata
+type CategorySource interface {
+	LoadCategories() (Categories, error)
+}
+
+// EmbeddedCategories loads the default categories shipped with the library
+type EmbeddedCategories struct{}
+
+func (EmbeddedCategories) LoadCategories() (Categories, error) {
+	var rows []struct {
+		Name       string `json:"name"`
+		ShortName  string `json:"short_name"`
+		Traduction string `json:"traduction"`
+		Icon       string `json:"icon"`
+		Color      string `json:"color"`
+	}
+	if err := json.Unmarshal(embeddedCategories, &rows); err != nil {
+		return nil, err
+	}
+
+	categories := make(Categories, 0, len(rows))
+	for i, row := range rows {
+		categories = append(categories, Category{
+			ID:         i + 1,
+			Name:       row.Name,
+			ShortName:  row.ShortName,
+			Traduction: sql.NullString{String: row.Traduction, Valid: row.Traduction != ""},
+			Icon:       row.Icon,
+			Color:      row.Color,
+		})
+	}
+	return categories, nil
+}
+
+// MongoCategories loads the categories stored in a Mongo collection
+type MongoCategories struct {
+	Collection *mongo.Collection
+}
+
+func (m MongoCategories) LoadCategories() (Categories, error) {
+	return LoadCategories(m.Collection)
+}
+
+// CategoryRegistry is the single place to look up category metadata
+type CategoryRegistry struct {
+	Localized bool // Use the Traduction of the categories as display name
+
+	categories  Categories
+	byName      map[string]int
+	byShortName map[string]int
+}
+
+// NewCategoryRegistry indexes the given categories, skipping the deleted ones
+func NewCategoryRegistry(categories Categories) *CategoryRegistry {
+	registry := &CategoryRegistry{
+		byName:      map[string]int{},
+		byShortName: map[string]int{},
+	}
+	for _, category := range categories {
+		if category.Deleted {
+			continue
+		}
+		registry.categories = append(registry.categories, category)
+		index := len(registry.categories) - 1
+		// Subcategory rows share the name of their category, keep the first row
+		if _, exists := registry.byName[normalizeCategoryKey(category.Name)]; !exists {
+			registry.byName[normalizeCategoryKey(category.Name)] = index
+		}
+		if category.Traduction.Valid && category.Traduction.String != "" {
+			if _, exists := registry.byName[normalizeCategoryKey(category.Traduction.String)]; !exists {
+				registry.byName[normalizeCategoryKey(category.Traduction.String)] = index
+			}
+		}
+		if category.ShortName != "" {
+			registry.byShortName[normalizeCategoryKey(category.ShortName)] = index
+		}
+	}
+	return registry
+}
+
+// LoadCategoryRegistry builds a registry from the categories of source
+func LoadCategoryRegistry(source CategorySource) (*CategoryRegistry, error) {
+	categories, err := source.LoadCategories()
+	if err != nil {
+		return nil, err
+	}
+	return NewCategoryRegistry(categories), nil
+}
+
+// DefaultCategoryRegistry returns a registry with the embedded categories
+func DefaultCategoryRegistry() *CategoryRegistry {
+	return NewCategoryRegistry(defaultCategories())
+}
+
+// Categories returns the registered categories in their original order
+func (r *CategoryRegistry) Categories() Categories {
+	return append(Categories{}, r.categories...)
+}
+
+// Get looks a category up by name, traduction or short name
+func (r *CategoryRegistry) Get(name string) (Category, bool) {
+	key := normalizeCategoryKey(name)
+	if index, ok := r.byName[key]; ok {
+		return r.categories[index], true
+	}
+	if index, ok := r.byShortName[key]; ok {
+		return r.categories[index], true
+	}
+	return Category{}, false
+}
+
+// GetByShortName looks a category up by its short name only
+func (r *CategoryRegistry) GetByShortName(shortName string) (Category, bool) {
+	if index, ok := r.byShortName[normalizeCategoryKey(shortName)]; ok {
+		return r.categories[index], true
+	}
+	return Category{}, false
+}
+
+// Icon returns the icon of a category, or the icon of "other" when unknown
+func (r *CategoryRegistry) Icon(name string) string {
+	if category, ok := r.Get(name); ok && category.Icon != "" {
+		return category.Icon
+	}
+	if other, ok := r.GetByShortName(CategoryOther); ok && other.Icon != "" {
+		return other.Icon
+	}
+	return DefaultCategoryIcon
+}
+
+// Color returns the color of a category, empty when unknown
+func (r *CategoryRegistry) Color(name string) string {
+	category, _ := r.Get(name)
+	return category.Color
+}
+
+// DisplayName returns the name to show for a category, translated when the registry is localized
+func (r *CategoryRegistry) DisplayName(name string) string {
+	category, ok := r.Get(name)
+	if !ok {
+		return name
+	}
+	if r.Localized {
+		return category.LocalizedName()
+	}
+	return category.Name
+}
+
+/* ╭──────────────────────────────────────────╮ */
+/* │              AUX FUNCTIONS               │ */
+/* ╰──────────────────────────────────────────╯ */
+func defaultCategories() Categories {
+	categories, err := EmbeddedCategories{}.LoadCategories()
+	if err != nil {
+		panic("invalid embedded categories: " + err.Error())
+	}
+	return categories
+}
+
+func normalizeCategoryKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
@@ -1,7 +1,23 @@
 package models
 
+import "time"
+
 type Task struct {
 	Name        string
 	Category    string
+	Subcategory string
+	Concept     string
 	TimeForTask float64 // Time spent on the task in hours
+	Done        bool    // Set on checklist items, whether the item is checked
+	Children    []Task  // Sub-tasks, like the checklist items of a card
+
+	// Where the task comes from
+	Source   string    // Name of the task source, like "trello"
+	SourceID string    // ID of the task in its source
+	Project  string    // Board, repository or project of the task
+	URL      string    // Link to the task in its source
+	Labels   []string  // Label or tag names
+	Client   string    // Client the work was done for, when the source knows it
+	Start    time.Time // When the work started, zero when unknown
+	End      time.Time // When the work ended, zero when unknown
 }
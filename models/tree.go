@@ -0,0 +1,290 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+type CategoryLevel int
+
+const (
+	CategoryLevelCategory CategoryLevel = iota
+	CategoryLevelSubcategory
+	CategoryLevelConcept
+)
+
+func (l CategoryLevel) String() string {
+	switch l {
+	case CategoryLevelCategory:
+		return "Category"
+	case CategoryLevelSubcategory:
+		return "Subcategory"
+	case CategoryLevelConcept:
+		return "Concept"
+	default:
+		return "?"
+	}
+}
+
+// CategoryNode is a category, subcategory or concept with the values assigned directly to it
+type CategoryNode struct {
+	Name     string
+	Icon     string
+	Level    CategoryLevel
+	Concept  *Concept // Only set for concept nodes
+	Children []*CategoryNode
+
+	Hours  float64 // Hours of the tasks assigned to this node only
+	Amount float64 // Amount of the incomes assigned to this node only
+	Count  int     // Tasks and incomes assigned to this node only
+}
+
+// CategoryTree is the category/subcategory/concept hierarchy
+type CategoryTree struct {
+	Roots []*CategoryNode
+}
+
+// NewCategoryTree builds the hierarchy from the categories table rows, where a
+// row with Subcategory is a child of the row with the same Name, and concepts
+// hang off the row referenced by their CategoryID.
+func NewCategoryTree(categories Categories, concepts Concepts) *CategoryTree {
+	tree := &CategoryTree{}
+	byID := map[int]*CategoryNode{}
+
+	for _, category := range categories {
+		if category.Deleted {
+			continue
+		}
+		node := tree.ensure(Classification{Category: category.Name, Subcategory: category.Subcategory})
+		if node.Icon == "" {
+			node.Icon = category.Icon
+		}
+		byID[category.ID] = node
+	}
+
+	for i := range concepts {
+		parent, ok := byID[concepts[i].CategoryID]
+		if !ok {
+			continue
+		}
+		parent.Children = append(parent.Children, &CategoryNode{
+			Name:    concepts[i].Name,
+			Icon:    concepts[i].Icon,
+			Level:   CategoryLevelConcept,
+			Concept: &concepts[i],
+		})
+	}
+
+	return tree
+}
+
+// Find returns the deepest node matching the classification, nil when the category is unknown
+func (t *CategoryTree) Find(classification Classification) *CategoryNode {
+	node := findChild(t.Roots, classification.Category)
+	if node == nil {
+		return nil
+	}
+	if classification.Subcategory != "" {
+		if child := findChild(node.Children, classification.Subcategory); child != nil {
+			node = child
+		}
+	}
+	if classification.Concept != "" {
+		if child := findChild(node.Children, classification.Concept); child != nil {
+			node = child
+		}
+	}
+	return node
+}
+
+// Walk visits every node in order with the classification that leads to it
+func (t *CategoryTree) Walk(visit func(node *CategoryNode, classification Classification)) {
+	for _, root := range t.Roots {
+		walkNode(root, Classification{}, visit)
+	}
+}
+
+// AddTask assigns the hours of a task to its node, creating the missing nodes
+func (t *CategoryTree) AddTask(task Task) {
+	node := t.ensure(Classification{Category: task.Category, Subcategory: task.Subcategory, Concept: task.Concept})
+	node.Hours += task.TimeForTask
+	node.Count++
+}
+
+// AddIncome assigns the amount of an income to its node, creating the missing nodes
+func (t *CategoryTree) AddIncome(income Income) {
+	node := t.ensure(Classification{Category: income.Category, Subcategory: income.Subcategory, Concept: income.Concept})
+	node.Amount += income.AmountReceived
+	node.Count++
+}
+
+// AddTasks assigns every task to the tree
+func (t *CategoryTree) AddTasks(tasks []Task) {
+	for _, task := range tasks {
+		t.AddTask(task)
+	}
+}
+
+// AddIncomes assigns every income to the tree
+func (t *CategoryTree) AddIncomes(incomes Incomes) {
+	for _, income := range incomes {
+		t.AddIncome(income)
+	}
+}
+
+// TotalHours rolls the hours of the node and all its descendants up
+func (n *CategoryNode) TotalHours() float64 {
+	total := n.Hours
+	for _, child := range n.Children {
+		total += child.TotalHours()
+	}
+	return total
+}
+
+// TotalAmount rolls the amount of the node and all its descendants up
+func (n *CategoryNode) TotalAmount() float64 {
+	total := n.Amount
+	for _, child := range n.Children {
+		total += child.TotalAmount()
+	}
+	return total
+}
+
+// TotalCount rolls the count of the node and all its descendants up
+func (n *CategoryNode) TotalCount() int {
+	total := n.Count
+	for _, child := range n.Children {
+		total += child.TotalCount()
+	}
+	return total
+}
+
+// TotalHours of the whole tree
+func (t *CategoryTree) TotalHours() float64 {
+	var total float64
+	for _, root := range t.Roots {
+		total += root.TotalHours()
+	}
+	return total
+}
+
+// TotalAmount of the whole tree
+func (t *CategoryTree) TotalAmount() float64 {
+	var total float64
+	for _, root := range t.Roots {
+		total += root.TotalAmount()
+	}
+	return total
+}
+
+// Prune returns a copy of the tree without the nodes that have nothing assigned
+func (t *CategoryTree) Prune() *CategoryTree {
+	return &CategoryTree{Roots: pruneNodes(t.Roots)}
+}
+
+// Render draws the tree for the terminal with the rolled up totals of each node
+func (t *CategoryTree) Render() string {
+	var b strings.Builder
+	for _, root := range t.Roots {
+		fmt.Fprintf(&b, "%s\n", formatNode(root))
+		renderChildren(&b, root.Children, "")
+	}
+	return b.String()
+}
+
+/* ╭──────────────────────────────────────────╮ */
+/* │              AUX FUNCTIONS               │ */
+/* ╰──────────────────────────────────────────╯ */
+func (t *CategoryTree) ensure(classification Classification) *CategoryNode {
+	name := classification.Category
+	if name == "" {
+		name = "Other"
+	}
+	node := findChild(t.Roots, name)
+	if node == nil {
+		node = &CategoryNode{Name: name, Level: CategoryLevelCategory}
+		t.Roots = append(t.Roots, node)
+	}
+	if classification.Subcategory != "" {
+		child := findChild(node.Children, classification.Subcategory)
+		if child == nil {
+			child = &CategoryNode{Name: classification.Subcategory, Level: CategoryLevelSubcategory}
+			node.Children = append(node.Children, child)
+		}
+		node = child
+	}
+	if classification.Concept != "" {
+		child := findChild(node.Children, classification.Concept)
+		if child == nil {
+			child = &CategoryNode{Name: classification.Concept, Level: CategoryLevelConcept}
+			node.Children = append(node.Children, child)
+		}
+		node = child
+	}
+	return node
+}
+
+func walkNode(node *CategoryNode, parent Classification, visit func(*CategoryNode, Classification)) {
+	classification := parent
+	switch node.Level {
+	case CategoryLevelCategory:
+		classification.Category = node.Name
+	case CategoryLevelSubcategory:
+		classification.Subcategory = node.Name
+	case CategoryLevelConcept:
+		classification.Concept = node.Name
+	}
+	visit(node, classification)
+	for _, child := range node.Children {
+		walkNode(child, classification, visit)
+	}
+}
+
+func findChild(nodes []*CategoryNode, name string) *CategoryNode {
+	for _, node := range nodes {
+		if strings.EqualFold(node.Name, name) {
+			return node
+		}
+	}
+	return nil
+}
+
+func pruneNodes(nodes []*CategoryNode) []*CategoryNode {
+	var pruned []*CategoryNode
+	for _, node := range nodes {
+		if node.TotalCount() == 0 {
+			continue
+		}
+		copied := *node
+		copied.Children = pruneNodes(node.Children)
+		pruned = append(pruned, &copied)
+	}
+	return pruned
+}
+
+func renderChildren(b *strings.Builder, children []*CategoryNode, prefix string) {
+	for i, child := range children {
+		branch, indent := "├── ", "│   "
+		if i == len(children)-1 {
+			branch, indent = "└── ", "    "
+		}
+		fmt.Fprintf(b, "%s%s%s\n", prefix, branch, formatNode(child))
+		renderChildren(b, child.Children, prefix+indent)
+	}
+}
+
+func formatNode(node *CategoryNode) string {
+	label := node.Name
+	if node.Icon != "" {
+		label = node.Icon + " " + label
+	}
+	var values []string
+	if hours := node.TotalHours(); hours != 0 {
+		values = append(values, fmt.Sprintf("%.2fh", hours))
+	}
+	if amount := node.TotalAmount(); amount != 0 {
+		values = append(values, fmt.Sprintf("%.2f€", amount))
+	}
+	values = append(values, fmt.Sprintf("%d", node.TotalCount()))
+	return fmt.Sprintf("%s (%s)", label, strings.Join(values, " · "))
+}
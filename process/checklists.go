@@ -0,0 +1,70 @@
+package process
+
+import (
+	"fmt"
+	"sort"
+
+	"txeo-tools-library/models"
+
+	"github.com/adlio/trello"
+)
+
+// checkItemComplete is the state of a checked checklist item
+const checkItemComplete = "complete"
+
+// GetSubTasks turns the checklist items of a card into tasks, each one with
+// its own duration (taken from its name, like "Fix login (1h)") and category
+func GetSubTasks(checklists []*trello.Checklist) ([]models.Task, []string) {
+	checklists = append([]*trello.Checklist{}, checklists...)
+	sort.SliceStable(checklists, func(i, j int) bool { return checklists[i].Pos < checklists[j].Pos })
+
+	var tasks []models.Task
+	var warnings []string
+	for _, checklist := range checklists {
+		items := append([]trello.CheckItem{}, checklist.CheckItems...)
+		sort.SliceStable(items, func(i, j int) bool { return items[i].Pos < items[j].Pos })
+
+		for _, item := range items {
+			cleaned, hours, _, itemWarnings := ParseDuration(item.Name)
+			for _, warning := range itemWarnings {
+				warnings = append(warnings, fmt.Sprintf("%s: %s", item.Name, warning))
+			}
+			tasks = append(tasks, models.Task{
+				Name:        cleaned,
+				Category:    GetTaskCategory(cleaned),
+				TimeForTask: hours,
+				Done:        item.State == checkItemComplete,
+			})
+		}
+	}
+	return tasks, warnings
+}
+
+// rollUpTime applies the time of the sub-tasks to a task: the explicit time of
+// the card wins, the sum of its completed checklist items is used otherwise,
+// as the pending ones haven't been worked yet
+func rollUpTime(task *models.Task, explicit bool) []string {
+	childrenTime := roundHours(doneTime(task.Children))
+	if len(task.Children) == 0 || childrenTime == 0 {
+		return nil
+	}
+	if !explicit {
+		task.TimeForTask = childrenTime
+		return nil
+	}
+	if childrenTime != task.TimeForTask {
+		return []string{fmt.Sprintf("card says %.2fh but its completed checklist items add up to %.2fh, using the card", task.TimeForTask, childrenTime)}
+	}
+	return nil
+}
+
+// doneTime sums the time of the completed sub-tasks
+func doneTime(tasks []models.Task) float64 {
+	total := 0.0
+	for _, task := range tasks {
+		if task.Done {
+			total += task.TimeForTask
+		}
+	}
+	return total
+}
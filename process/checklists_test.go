@@ -0,0 +1,88 @@
+package process
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/adlio/trello"
+)
+
+// checklistCard reads the card of the Trello fake fixtures with a checklist
+func checklistCard(t *testing.T) *trello.Card {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("..", "trello", "testdata", "fake", "olympics.json"))
+	if err != nil {
+		t.Fatalf("error reading fixtures: %v", err)
+	}
+	var fixtures struct {
+		Cards []*trello.Card `json:"boards/617c56690fcb27430e740522/cards"`
+	}
+	if err := json.Unmarshal(data, &fixtures); err != nil {
+		t.Fatalf("error decoding fixtures: %v", err)
+	}
+	for _, card := range fixtures.Cards {
+		if card.ID == "6710000000000000000000c6" {
+			return card
+		}
+	}
+	t.Fatal("card c6 not found in the fixtures")
+	return nil
+}
+
+func TestGetSubTasks(t *testing.T) {
+	children, warnings := GetSubTasks(checklistCard(t).Checklists)
+	want := []struct {
+		name  string
+		hours float64
+		done  bool
+	}{
+		{"Fix login", 1, true},
+		{"Testing the payment form", 1.5, true},
+		{"Call with the client to review", 0.5, false},
+	}
+	if len(children) != len(want) || len(warnings) != 0 {
+		t.Fatalf("got %+v with warnings %q, want the 3 checklist items", children, warnings)
+	}
+	for i, child := range children {
+		if child.Name != want[i].name || child.TimeForTask != want[i].hours || child.Done != want[i].done || child.Category == "" {
+			t.Errorf("item %d = %+v, want %+v", i, child, want[i])
+		}
+	}
+}
+
+func TestGetTaskFromCardRollsUpCompletedItems(t *testing.T) {
+	tests := []struct {
+		name     string
+		pending  bool // Mark every item as incomplete
+		hours    float64
+		warnings string // Substring of the only warning, none when empty
+	}{
+		{"Checkout flow fixes", false, 2.5, ""},
+		{"Checkout flow fixes (2.5h)", false, 2.5, ""},
+		{"Checkout flow fixes (2h)", false, 2, "completed checklist items add up to 2.50h"},
+		{"Checkout flow fixes", true, 0, ""},
+	}
+	for _, test := range tests {
+		card := checklistCard(t)
+		card.Name = test.name
+		if test.pending {
+			for i := range card.Checklists[0].CheckItems {
+				card.Checklists[0].CheckItems[i].State = "incomplete"
+			}
+		}
+
+		task, warnings := GetTaskFromCard(card, nil, "")
+		if task.TimeForTask != test.hours || len(task.Children) != 3 {
+			t.Errorf("%q (pending %v): got %.2fh and %d children, want %.2fh", test.name, test.pending, task.TimeForTask, len(task.Children), test.hours)
+		}
+		switch {
+		case test.warnings == "" && len(warnings) != 0:
+			t.Errorf("%q: got warnings %q, want none", test.name, warnings)
+		case test.warnings != "" && (len(warnings) != 1 || !strings.Contains(warnings[0], test.warnings)):
+			t.Errorf("%q: got warnings %q, want %q", test.name, warnings, test.warnings)
+		}
+	}
+}
@@ -0,0 +1,273 @@
+package process
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"txeo-tools-library/models"
+
+	"github.com/adlio/trello"
+)
+
+// Sources a task duration can be taken from
+const (
+	DurationFromField       = "field"
+	DurationFromName        = "name"
+	DurationFromDescription = "description"
+)
+
+// maxTaskHours is the limit above which a parsed duration is reported as suspicious
+const maxTaskHours = 24
+
+// invalidRangePlaceholder stands for an invalid time range while the other durations are parsed
+const invalidRangePlaceholder = "\x00"
+
+var (
+	// [3] --> 3 hours
+	bracketHoursRegex = regexp.MustCompile(`\[\s*(\d+(?:[.,]\d+)?)\s*\]`)
+	// 9h-10h, 9h30 - 11h --> 1 and 1.5 hours
+	rangeHoursRegex = regexp.MustCompile(`(?i)\b(\d{1,2})h(\d{2})?\s*-\s*(\d{1,2})h(\d{2})?\b`)
+	// 2h, 1h30, 1h 30m, 1,5 h, 2 hours, 3 horas
+	hoursRegex = regexp.MustCompile(`(?i)\b(\d+(?:[.,]\d+)?)\s*(?:hours?|horas?|hrs?|h)(?:(\d{1,2})|\s*(\d{1,2})\s*(?:minutes?|minutos?|mins?|m))?\b`)
+	// 45m, 30 min, 15 minutos
+	minutesRegex = regexp.MustCompile(`(?i)\b(\d+)\s*(?:minutes?|minutos?|mins?|m)\b`)
+
+	emptyBracketsRegex = regexp.MustCompile(`\(\s*\)|\[\s*\]|\{\s*\}`)
+	spacesRegex        = regexp.MustCompile(`\s+`)
+)
+
+// TaskDuration is the result of extracting a duration from a task
+type TaskDuration struct {
+	Name     string   // Task name without the duration annotation
+	Hours    float64  // Duration normalized to hours
+	Found    bool     // Whether a duration was found at all
+	Source   string   // Where the duration was taken from (field, name or description)
+	Warnings []string // Anything suspicious found while parsing
+}
+
+// ParseDuration finds every duration annotation in text, removes them and
+// returns the cleaned text with the total normalized to hours.
+func ParseDuration(text string) (cleaned string, hours float64, found bool, warnings []string) {
+	matches := 0
+	cleaned = text
+
+	cleaned = bracketHoursRegex.ReplaceAllStringFunc(cleaned, func(match string) string {
+		value := bracketHoursRegex.FindStringSubmatch(match)[1]
+		h, err := parseDecimal(value)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("invalid duration %q", match))
+			return match
+		}
+		hours += h
+		matches++
+		return " "
+	})
+
+	// Invalid ranges are kept in the text, hidden from the hours pattern until the end
+	var invalidRanges []string
+	cleaned = rangeHoursRegex.ReplaceAllStringFunc(cleaned, func(match string) string {
+		groups := rangeHoursRegex.FindStringSubmatch(match)
+		from, okFrom := clockMinutes(groups[1], groups[2])
+		to, okTo := clockMinutes(groups[3], groups[4])
+		if !okFrom || !okTo {
+			warnings = append(warnings, fmt.Sprintf("invalid time range %q", match))
+			invalidRanges = append(invalidRanges, match)
+			return invalidRangePlaceholder
+		}
+		if to <= from {
+			to += 24 * 60
+			warnings = append(warnings, fmt.Sprintf("time range %q ends past midnight, read as %.2fh", match, float64(to-from)/60))
+		}
+		hours += float64(to-from) / 60
+		matches++
+		return " "
+	})
+
+	cleaned = hoursRegex.ReplaceAllStringFunc(cleaned, func(match string) string {
+		groups := hoursRegex.FindStringSubmatch(match)
+		h, err := parseDecimal(groups[1])
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("invalid duration %q", match))
+			return match
+		}
+		minutesValue := groups[2]
+		if minutesValue == "" {
+			minutesValue = groups[3]
+		}
+		if minutesValue != "" {
+			if strings.ContainsAny(groups[1], ".,") {
+				warnings = append(warnings, fmt.Sprintf("decimal hours combined with minutes in %q", strings.TrimSpace(match)))
+			}
+			m, _ := strconv.Atoi(minutesValue)
+			if m >= 60 {
+				warnings = append(warnings, fmt.Sprintf("minutes out of range in %q", strings.TrimSpace(match)))
+			}
+			h += float64(m) / 60
+		}
+		hours += h
+		matches++
+		return " "
+	})
+
+	cleaned = minutesRegex.ReplaceAllStringFunc(cleaned, func(match string) string {
+		m, err := strconv.Atoi(minutesRegex.FindStringSubmatch(match)[1])
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("invalid duration %q", match))
+			return match
+		}
+		hours += float64(m) / 60
+		matches++
+		return " "
+	})
+
+	for _, invalidRange := range invalidRanges {
+		cleaned = strings.Replace(cleaned, invalidRangePlaceholder, invalidRange, 1)
+	}
+	if matches == 0 {
+		return text, 0, false, warnings
+	}
+	if matches > 1 {
+		warnings = append(warnings, fmt.Sprintf("%d durations found in %q, using their sum", matches, text))
+	}
+	if hours == 0 {
+		warnings = append(warnings, fmt.Sprintf("zero duration in %q", text))
+	}
+	if hours > maxTaskHours {
+		warnings = append(warnings, fmt.Sprintf("duration of %.2fh in %q looks too long", hours, text))
+	}
+
+	return cleanTaskName(cleaned), roundHours(hours), true, warnings
+}
+
+// GetTaskDuration picks the duration of a task, preferring the custom field
+// value, then the task name and finally its description.
+// fieldValue may be nil when the card has no value for the configured field.
+func GetTaskDuration(name, description string, fieldValue interface{}) TaskDuration {
+	result := TaskDuration{Name: name}
+
+	cleanedName, nameHours, nameFound, nameWarnings := ParseDuration(name)
+	result.Name = cleanedName
+	result.Warnings = append(result.Warnings, nameWarnings...)
+
+	if fieldValue != nil {
+		fieldHours, err := fieldValueToHours(fieldValue)
+		if err != nil {
+			result.Warnings = append(result.Warnings, err.Error())
+		} else {
+			result.Hours = fieldHours
+			result.Found = true
+			result.Source = DurationFromField
+			if nameFound && nameHours != fieldHours {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("custom field says %.2fh but name says %.2fh, using the custom field", fieldHours, nameHours))
+			}
+			return result
+		}
+	}
+
+	if nameFound {
+		result.Hours = nameHours
+		result.Found = true
+		result.Source = DurationFromName
+	}
+
+	if description == "" {
+		return result
+	}
+	_, descriptionHours, descriptionFound, descriptionWarnings := ParseDuration(description)
+	if !descriptionFound {
+		return result
+	}
+	if nameFound {
+		if descriptionHours != nameHours {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("description says %.2fh but name says %.2fh, using the name", descriptionHours, nameHours))
+		}
+		return result
+	}
+	result.Warnings = append(result.Warnings, descriptionWarnings...)
+	result.Hours = descriptionHours
+	result.Found = true
+	result.Source = DurationFromDescription
+	return result
+}
+
+// GetTaskFromCard builds a task from a Trello card, taking its duration from
+// the hoursField custom field (if any) or from the card name and description.
+// Checklist items become the children of the task, and the time of the
+// completed ones is used when the card has none.
+// customFields are the board custom field definitions, needed to resolve the
+// field by name.
+func GetTaskFromCard(card *trello.Card, customFields []*trello.CustomField, hoursField string) (models.Task, []string) {
+	var fieldValue interface{}
+	if hoursField != "" && len(card.CustomFieldItems) > 0 {
+		fieldValue = card.CustomFields(customFields)[hoursField]
+	}
+
+	duration := GetTaskDuration(card.Name, card.Desc, fieldValue)
+	task := models.Task{
+		Name:        duration.Name,
+		Category:    GetTaskCategory(duration.Name),
+		TimeForTask: duration.Hours,
+	}
+
+	cardWarnings := duration.Warnings
+	children, childWarnings := GetSubTasks(card.Checklists)
+	task.Children = children
+	cardWarnings = append(cardWarnings, childWarnings...)
+	cardWarnings = append(cardWarnings, rollUpTime(&task, duration.Found)...)
+
+	warnings := make([]string, 0, len(cardWarnings))
+	for _, warning := range cardWarnings {
+		warnings = append(warnings, fmt.Sprintf("%s: %s", card.Name, warning))
+	}
+	return task, warnings
+}
+
+/* ╭──────────────────────────────────────────╮ */
+/* │              AUX FUNCTIONS               │ */
+/* ╰──────────────────────────────────────────╯ */
+func fieldValueToHours(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case float64:
+		return roundHours(v), nil
+	case string:
+		if h, err := parseDecimal(strings.TrimSpace(v)); err == nil {
+			return roundHours(h), nil
+		}
+		_, h, found, _ := ParseDuration(v)
+		if !found {
+			return 0, fmt.Errorf("custom field value %q is not a duration", v)
+		}
+		return h, nil
+	default:
+		return 0, fmt.Errorf("custom field value %v is not a duration", value)
+	}
+}
+
+// clockMinutes reads the hour and minutes of a time range as minutes since midnight
+func clockMinutes(hour, minute string) (int, bool) {
+	h, _ := strconv.Atoi(hour)
+	m, _ := strconv.Atoi(minute)
+	return h*60 + m, h < 24 && m < 60
+}
+
+// parseDecimal accepts both "1.5" and "1,5"
+func parseDecimal(value string) (float64, error) {
+	return strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64)
+}
+
+func roundHours(hours float64) float64 {
+	return math.Round(hours*100) / 100
+}
+
+func cleanTaskName(name string) string {
+	name = emptyBracketsRegex.ReplaceAllString(name, " ")
+	name = spacesRegex.ReplaceAllString(name, " ")
+	return strings.Trim(name, " -–—:|,.+")
+}
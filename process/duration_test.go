@@ -0,0 +1,73 @@
+package process
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		text    string
+		name    string
+		hours   float64
+		found   bool
+		warning string // Substring of the only warning, none when empty
+	}{
+		{"Weekly call with IOC team [1.5]", "Weekly call with IOC team", 1.5, true, ""},
+		{"Weekly call [ 2,5 ]", "Weekly call", 2.5, true, ""},
+		{"Fix login redirect (2h)", "Fix login redirect", 2, true, ""},
+		{"Documentation for the handover 1h30", "Documentation for the handover", 1.5, true, ""},
+		{"Sprint planning 1h 15m", "Sprint planning", 1.25, true, ""},
+		{"Code review 45m", "Code review", 0.75, true, ""},
+		{"Code review - 30 minutos", "Code review", 0.5, true, ""},
+		{"Testing the payment form 1,5 h", "Testing the payment form", 1.5, true, ""},
+		{"Reunión semanal 2 horas", "Reunión semanal", 2, true, ""},
+		{"Deploy 9h-10h", "Deploy", 1, true, ""},
+		{"Workshop 9h30 - 11h", "Workshop", 1.5, true, ""},
+		{"Release night 23h-1h", "Release night", 2, true, `time range "23h-1h" ends past midnight, read as 2.00h`},
+		{"Estimate 30h-40h", "Estimate 30h-40h", 0, false, `invalid time range "30h-40h"`},
+		{"Estimate 30h-40h [2]", "Estimate 30h-40h", 2, true, `invalid time range "30h-40h"`},
+		{"Call [1] and follow up 30m", "Call and follow up", 1.5, true, "2 durations found"},
+		{"Migration 1,5h30", "Migration", 2, true, "decimal hours combined with minutes"},
+		{"Review 1h75", "Review", 2.25, true, "minutes out of range"},
+		{"Kick-off (0h)", "Kick-off", 0, true, "zero duration"},
+		{"Team offsite 30h", "Team offsite", 30, true, "looks too long"},
+		{"Slack thread with devops", "Slack thread with devops", 0, false, ""},
+	}
+	for _, test := range tests {
+		name, hours, found, warnings := ParseDuration(test.text)
+		if name != test.name || hours != test.hours || found != test.found {
+			t.Errorf("ParseDuration(%q) = %q, %.2fh, %v; want %q, %.2fh, %v", test.text, name, hours, found, test.name, test.hours, test.found)
+		}
+		switch {
+		case test.warning == "" && len(warnings) != 0:
+			t.Errorf("ParseDuration(%q): got warnings %q, want none", test.text, warnings)
+		case test.warning != "" && (len(warnings) != 1 || !strings.Contains(warnings[0], test.warning)):
+			t.Errorf("ParseDuration(%q): got warnings %q, want %q", test.text, warnings, test.warning)
+		}
+	}
+}
+
+func TestGetTaskDuration(t *testing.T) {
+	tests := []struct {
+		name, description string
+		field             interface{}
+		hours             float64
+		source            string
+		warnings          int
+	}{
+		{"Fix login redirect (2h)", "", nil, 2, DurationFromName, 0},
+		{"Fix login redirect (2h)", "", 1.5, 1.5, DurationFromField, 1},
+		{"Fix login redirect", "", "1h30", 1.5, DurationFromField, 0},
+		{"Fix login redirect", "", "soon", 0, "", 1},
+		{"Fix login redirect", "Took 3h", nil, 3, DurationFromDescription, 0},
+		{"Fix login redirect (2h)", "Took 3h", nil, 2, DurationFromName, 1},
+		{"Fix login redirect", "", nil, 0, "", 0},
+	}
+	for _, test := range tests {
+		got := GetTaskDuration(test.name, test.description, test.field)
+		if got.Name != "Fix login redirect" || got.Hours != test.hours || got.Source != test.source || got.Found != (test.source != "") || len(got.Warnings) != test.warnings {
+			t.Errorf("GetTaskDuration(%q, %q, %v) = %+v, want %.2fh from %q with %d warnings", test.name, test.description, test.field, got, test.hours, test.source, test.warnings)
+		}
+	}
+}
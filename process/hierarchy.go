@@ -0,0 +1,60 @@
+package process
+
+import (
+	"strings"
+
+	"txeo-tools-library/models"
+
+	"github.com/ozgio/strutil"
+)
+
+// Classify places a text in the category tree by looking for the tags of its
+// concepts. ok is false when no concept tag matches.
+func Classify(text string, tree *models.CategoryTree) (classification models.Classification, ok bool) {
+	textSlug := "-" + strutil.Slugify(text) + "-"
+	bestLength := 0
+	tree.Walk(func(node *models.CategoryNode, nodeClassification models.Classification) {
+		if node.Concept == nil {
+			return
+		}
+		for _, tag := range node.Concept.Tags {
+			tagSlug := tag.Slug
+			if tagSlug == "" {
+				tagSlug = strutil.Slugify(tag.Name)
+			}
+			// The longest tag wins so "full reg" beats "reg"
+			if tagSlug != "" && len(tagSlug) > bestLength && strings.Contains(textSlug, "-"+tagSlug+"-") {
+				classification = nodeClassification
+				bestLength = len(tagSlug)
+				ok = true
+			}
+		}
+	})
+	return classification, ok
+}
+
+// ClassifyTask fills the category, subcategory and concept of a task, using the
+// concept tags of the tree and falling back to GetTaskCategory.
+func ClassifyTask(task models.Task, tree *models.CategoryTree) models.Task {
+	if classification, ok := Classify(task.Name, tree); ok {
+		task.Category = classification.Category
+		task.Subcategory = classification.Subcategory
+		task.Concept = classification.Concept
+		return task
+	}
+	if task.Category == "" {
+		task.Category = GetTaskCategory(task.Name)
+	}
+	return task
+}
+
+// ClassifyIncome fills the subcategory and concept of an income from its title
+// and description, keeping its category when no concept tag matches.
+func ClassifyIncome(income models.Income, tree *models.CategoryTree) models.Income {
+	if classification, ok := Classify(income.Title+" "+income.Description, tree); ok {
+		income.Category = classification.Category
+		income.Subcategory = classification.Subcategory
+		income.Concept = classification.Concept
+	}
+	return income
+}
@@ -0,0 +1,114 @@
+package process
+
+import (
+	"strings"
+	"testing"
+
+	"txeo-tools-library/models"
+)
+
+// hierarchyTree is Implementation with a Registration subcategory holding
+// the lite and full registration concepts, and Meetings with a weekly concept
+func hierarchyTree() *models.CategoryTree {
+	categories := models.Categories{
+		{ID: 1, Name: "Implementation / Configuration tasks", Icon: "💪"},
+		{ID: 2, Name: "Implementation / Configuration tasks", Subcategory: "Registration"},
+		{ID: 3, Name: "Catchups / Meetings", Icon: "📅"},
+		{ID: 4, Name: "Old category", Deleted: true},
+	}
+	concepts := models.Concepts{
+		{CategoryID: 2, Name: "Registration", Tags: models.Tags{{Name: "reg"}}},
+		{CategoryID: 2, Name: "Full registration", Tags: models.Tags{{Name: "full reg"}, {Slug: "full-registration"}}},
+		{CategoryID: 3, Name: "Weekly", Tags: models.Tags{{Name: "weekly"}}},
+		{CategoryID: 4, Name: "Lost", Tags: models.Tags{{Name: "lost"}}},
+	}
+	return models.NewCategoryTree(categories, concepts)
+}
+
+func TestNewCategoryTree(t *testing.T) {
+	tree := hierarchyTree()
+
+	var got []string
+	tree.Walk(func(node *models.CategoryNode, classification models.Classification) {
+		got = append(got, node.Level.String()+" "+classification.Category+" / "+classification.Subcategory+" / "+classification.Concept)
+	})
+	want := []string{
+		"Category Implementation / Configuration tasks /  / ",
+		"Subcategory Implementation / Configuration tasks / Registration / ",
+		"Concept Implementation / Configuration tasks / Registration / Registration",
+		"Concept Implementation / Configuration tasks / Registration / Full registration",
+		"Category Catchups / Meetings /  / ",
+		"Concept Catchups / Meetings /  / Weekly",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got nodes\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if node := tree.Find(models.Classification{Category: "catchups / meetings", Concept: "Unknown"}); node == nil || node.Name != "Catchups / Meetings" || node.Icon != "📅" {
+		t.Errorf("got node %+v, want the Meetings category", node)
+	}
+	if node := tree.Find(models.Classification{Category: "Old category"}); node != nil {
+		t.Errorf("got deleted node %+v", node)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tree := hierarchyTree()
+	tests := []struct {
+		text string
+		want models.Classification
+		ok   bool
+	}{
+		{"Lite reg screens", models.Classification{Category: "Implementation / Configuration tasks", Subcategory: "Registration", Concept: "Registration"}, true},
+		{"Full reg screens", models.Classification{Category: "Implementation / Configuration tasks", Subcategory: "Registration", Concept: "Full registration"}, true},
+		{"Full registration flow", models.Classification{Category: "Implementation / Configuration tasks", Subcategory: "Registration", Concept: "Full registration"}, true},
+		{"Weekly call", models.Classification{Category: "Catchups / Meetings", Concept: "Weekly"}, true},
+		{"Regression tests", models.Classification{}, false},
+		{"Lost keys", models.Classification{}, false},
+	}
+	for _, test := range tests {
+		if got, ok := Classify(test.text, tree); got != test.want || ok != test.ok {
+			t.Errorf("Classify(%q) = %+v, %v; want %+v, %v", test.text, got, ok, test.want, test.ok)
+		}
+	}
+
+	task := ClassifyTask(models.Task{Name: "Slack thread with devops"}, tree)
+	if task.Category != "Slack / Teams Conversations" || task.Concept != "" {
+		t.Errorf("got %+v, want the keyword category without concept", task)
+	}
+	income := ClassifyIncome(models.Income{Title: "Invoice", Category: "Olympics"}, tree)
+	if income.Category != "Olympics" || income.Concept != "" {
+		t.Errorf("got %+v, want the income category kept", income)
+	}
+}
+
+func TestCategoryTreeTotals(t *testing.T) {
+	tree := hierarchyTree()
+	tree.AddTasks([]models.Task{
+		{Name: "Full reg screens", Category: "Implementation / Configuration tasks", Subcategory: "Registration", Concept: "Full registration", TimeForTask: 2},
+		{Name: "Reg fixes", Category: "Implementation / Configuration tasks", Subcategory: "Registration", TimeForTask: 1.5},
+		{Name: "Sprint demo", Category: "Demos", TimeForTask: 1},
+		{Name: "Unknown", TimeForTask: 0.5},
+	})
+	tree.AddIncomes(models.Incomes{{Title: "Registration", Category: "Implementation / Configuration tasks", Subcategory: "Registration", AmountReceived: 300}})
+
+	implementation := tree.Find(models.Classification{Category: "Implementation / Configuration tasks"})
+	if implementation.TotalHours() != 3.5 || implementation.TotalAmount() != 300 || implementation.TotalCount() != 3 || implementation.Count != 0 {
+		t.Errorf("got %.2fh, %.2f€ and %d (%d own) in Implementation", implementation.TotalHours(), implementation.TotalAmount(), implementation.TotalCount(), implementation.Count)
+	}
+	if tree.TotalHours() != 5 || tree.TotalAmount() != 300 {
+		t.Errorf("got %.2fh and %.2f€ in the tree", tree.TotalHours(), tree.TotalAmount())
+	}
+
+	want := `💪 Implementation / Configuration tasks (3.50h · 300.00€ · 3)
+└── Registration (3.50h · 300.00€ · 3)
+    └── Full registration (2.00h · 1)
+Demos (1.00h · 1)
+Other (0.50h · 1)
+`
+	if got := tree.Prune().Render(); got != want {
+		t.Errorf("got tree\n%s\nwant\n%s", got, want)
+	}
+	if meetings := tree.Find(models.Classification{Category: "Catchups / Meetings"}); meetings == nil {
+		t.Error("pruning changed the original tree")
+	}
+}
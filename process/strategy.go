@@ -0,0 +1,262 @@
+package process
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"txeo-tools-library/models"
+
+	"github.com/adlio/trello"
+)
+
+// Categorization strategies that can be chained in a config
+const (
+	StrategyLabels   = "labels"
+	StrategyField    = "field"
+	StrategyKeywords = "keywords"
+)
+
+// DefaultStrategies is the order used when a config doesn't set one
+var DefaultStrategies = []string{StrategyLabels, StrategyField, StrategyKeywords}
+
+// CardCategoryInput is what the strategies look at to categorize a card
+type CardCategoryInput struct {
+	Name   string
+	Labels []*trello.Label
+	Fields map[string]string // Custom field values by field name
+}
+
+// CategoryStrategy finds the category of a card, ok is false when it can't tell
+type CategoryStrategy interface {
+	Category(input CardCategoryInput) (category string, ok bool)
+}
+
+// KeywordStrategy guesses the category from the keywords of the card name
+type KeywordStrategy struct{}
+
+func (KeywordStrategy) Category(input CardCategoryInput) (string, bool) {
+	return GetTaskCategory(input.Name), true
+}
+
+// LabelStrategy maps label names, then label colors, to categories. Labels
+// named after a category (like the ones created by the label write-back) match
+// without configuration.
+type LabelStrategy struct {
+	Names  map[string]string // Label name -> category name or short name
+	Colors map[string]string // Label color -> category name or short name
+}
+
+func (s LabelStrategy) Category(input CardCategoryInput) (string, bool) {
+	for _, label := range input.Labels {
+		if target, ok := lookupFold(s.Names, label.Name); ok {
+			if category, ok := resolveCategory(target); ok {
+				return category, true
+			}
+		}
+		if category, ok := resolveCategory(label.Name); ok {
+			return category, true
+		}
+	}
+	for _, label := range input.Labels {
+		if target, ok := lookupFold(s.Colors, label.Color); ok {
+			if category, ok := resolveCategory(target); ok {
+				return category, true
+			}
+		}
+	}
+	return "", false
+}
+
+// FieldStrategy takes the category from a custom field, mapping its values
+// when Values is set or using the value as category name otherwise
+type FieldStrategy struct {
+	Field  string
+	Values map[string]string // Field value -> category name or short name
+}
+
+func (s FieldStrategy) Category(input CardCategoryInput) (string, bool) {
+	if s.Field == "" {
+		return "", false
+	}
+	value, ok := lookupFold(input.Fields, s.Field)
+	if !ok || strings.TrimSpace(value) == "" {
+		return "", false
+	}
+	if target, ok := lookupFold(s.Values, value); ok {
+		value = target
+	}
+	return resolveCategory(value)
+}
+
+// CompositeStrategy tries each strategy in turn, the first that knows wins
+type CompositeStrategy []CategoryStrategy
+
+func (s CompositeStrategy) Category(input CardCategoryInput) (string, bool) {
+	for _, strategy := range s {
+		if category, ok := strategy.Category(input); ok {
+			return category, true
+		}
+	}
+	return "", false
+}
+
+// Categorize returns the category found by strategy, "Other" when none
+func Categorize(strategy CategoryStrategy, input CardCategoryInput) string {
+	if category, ok := strategy.Category(input); ok {
+		return category
+	}
+	return categoryName(models.CategoryOther)
+}
+
+// LabelMapping maps label names and colors to categories
+type LabelMapping struct {
+	Names  map[string]string `json:"names,omitempty"`
+	Colors map[string]string `json:"colors,omitempty"`
+}
+
+// CategorizationConfig selects the strategies used to categorize the cards of
+// each board:
+//
+//	{
+//	  "strategies": ["labels", "field", "keywords"],
+//	  "labels": {"names": {"Reunión": "meetings"}, "colors": {"purple": "conversations"}},
+//	  "field": "Categoría",
+//	  "boards": {"Olympics": {"strategies": ["labels", "keywords"]}}
+//	}
+type CategorizationConfig struct {
+	Strategies  []string                        `json:"strategies,omitempty"`
+	Labels      LabelMapping                    `json:"labels"`
+	Field       string                          `json:"field,omitempty"`
+	FieldValues map[string]string               `json:"fieldValues,omitempty"`
+	Boards      map[string]CategorizationConfig `json:"boards,omitempty"` // Overrides by board name or ID
+}
+
+// LoadCategorizationConfig reads a categorization config from a JSON file
+func LoadCategorizationConfig(path string) (CategorizationConfig, error) {
+	var config CategorizationConfig
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return config, fmt.Errorf("error reading categorization config: %w", err)
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("error decoding categorization config %s: %w", path, err)
+	}
+	return config, config.Validate()
+}
+
+// Validate checks the strategy names of the config and its boards
+func (c CategorizationConfig) Validate() error {
+	for _, name := range c.Strategies {
+		if name != StrategyLabels && name != StrategyField && name != StrategyKeywords {
+			return fmt.Errorf("unknown categorization strategy %q", name)
+		}
+	}
+	for board, boardConfig := range c.Boards {
+		if err := boardConfig.Validate(); err != nil {
+			return fmt.Errorf("board %s: %w", board, err)
+		}
+	}
+	return nil
+}
+
+// Strategy returns the strategy of a board, given its name or ID. The board
+// settings override the global ones, the mappings are merged.
+func (c CategorizationConfig) Strategy(board string) CompositeStrategy {
+	config := c.forBoard(board)
+	names := config.Strategies
+	if len(names) == 0 {
+		names = DefaultStrategies
+	}
+
+	var strategy CompositeStrategy
+	for _, name := range names {
+		switch name {
+		case StrategyLabels:
+			strategy = append(strategy, LabelStrategy{Names: config.Labels.Names, Colors: config.Labels.Colors})
+		case StrategyField:
+			strategy = append(strategy, FieldStrategy{Field: config.Field, Values: config.FieldValues})
+		case StrategyKeywords:
+			strategy = append(strategy, KeywordStrategy{})
+		}
+	}
+	return strategy
+}
+
+/* ╭──────────────────────────────────────────╮ */
+/* │              AUX FUNCTIONS               │ */
+/* ╰──────────────────────────────────────────╯ */
+func (c CategorizationConfig) forBoard(board string) CategorizationConfig {
+	override, ok := c.Boards[board]
+	if !ok {
+		for name, boardConfig := range c.Boards {
+			if strings.EqualFold(name, board) {
+				override, ok = boardConfig, true
+				break
+			}
+		}
+	}
+	if !ok {
+		return c
+	}
+
+	merged := CategorizationConfig{
+		Strategies:  c.Strategies,
+		Field:       c.Field,
+		Labels:      LabelMapping{Names: mergeMaps(c.Labels.Names, override.Labels.Names), Colors: mergeMaps(c.Labels.Colors, override.Labels.Colors)},
+		FieldValues: mergeMaps(c.FieldValues, override.FieldValues),
+	}
+	if len(override.Strategies) > 0 {
+		merged.Strategies = override.Strategies
+	}
+	if override.Field != "" {
+		merged.Field = override.Field
+	}
+	return merged
+}
+
+// resolveCategory returns the name of the category called name (or with that
+// short name or traduction), accepting label names prefixed with the category icon
+func resolveCategory(name string) (string, bool) {
+	name = strings.TrimSpace(name)
+	registry := CategoryRegistry()
+	if category, ok := registry.Get(name); ok {
+		return category.Name, true
+	}
+	for _, category := range registry.Categories() {
+		if category.Icon == "" || !strings.HasPrefix(name, category.Icon) {
+			continue
+		}
+		if strings.EqualFold(strings.TrimSpace(strings.TrimPrefix(name, category.Icon)), category.Name) {
+			return category.Name, true
+		}
+	}
+	return "", false
+}
+
+func lookupFold(values map[string]string, key string) (string, bool) {
+	if value, ok := values[key]; ok {
+		return value, true
+	}
+	for k, value := range values {
+		if strings.EqualFold(strings.TrimSpace(k), strings.TrimSpace(key)) {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+func mergeMaps(base, override map[string]string) map[string]string {
+	if len(override) == 0 {
+		return base
+	}
+	merged := map[string]string{}
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range override {
+		merged[k] = v
+	}
+	return merged
+}
@@ -0,0 +1,135 @@
+package process
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/adlio/trello"
+)
+
+const (
+	meetingsCategory       = "Catchups / Meetings"
+	implementationCategory = "Implementation / Configuration tasks"
+	documentationCategory  = "Emails / Documentation"
+	conversationsCategory  = "Slack / Teams Conversations"
+	otherCategory          = "Other"
+)
+
+func TestLabelStrategy(t *testing.T) {
+	strategy := LabelStrategy{
+		Names:  map[string]string{"Reunión": "meetings", "Broken": "not a category"},
+		Colors: map[string]string{"purple": "conversations"},
+	}
+	tests := []struct {
+		name   string
+		labels []*trello.Label
+		want   string
+		ok     bool
+	}{
+		{"mapped name", []*trello.Label{{Name: "reunión", Color: "purple"}}, meetingsCategory, true},
+		{"category name", []*trello.Label{{Name: "Emails / Documentation"}}, documentationCategory, true},
+		{"category short name", []*trello.Label{{Name: "implementation"}}, implementationCategory, true},
+		{"label with icon", []*trello.Label{{Name: "📧 Emails / Documentation"}}, documentationCategory, true},
+		{"names before colors", []*trello.Label{{Name: "Urgent", Color: "purple"}, {Name: "Reunión"}}, meetingsCategory, true},
+		{"color", []*trello.Label{{Name: "Urgent", Color: "purple"}}, conversationsCategory, true},
+		{"unknown mapped category", []*trello.Label{{Name: "Broken"}}, "", false},
+		{"no labels", nil, "", false},
+	}
+	for _, test := range tests {
+		if got, ok := strategy.Category(CardCategoryInput{Name: "Weekly call", Labels: test.labels}); got != test.want || ok != test.ok {
+			t.Errorf("%s: got %q, %v; want %q, %v", test.name, got, ok, test.want, test.ok)
+		}
+	}
+}
+
+func TestFieldStrategy(t *testing.T) {
+	strategy := FieldStrategy{Field: "Categoría", Values: map[string]string{"Reunión": "meetings"}}
+	tests := []struct {
+		fields map[string]string
+		want   string
+		ok     bool
+	}{
+		{map[string]string{"categoría": "reunión"}, meetingsCategory, true},
+		{map[string]string{"Categoría": "conversations"}, conversationsCategory, true},
+		{map[string]string{"Categoría": "Slack / Teams Conversations"}, conversationsCategory, true},
+		{map[string]string{"Categoría": "Sales"}, "", false},
+		{map[string]string{"Categoría": " "}, "", false},
+		{map[string]string{"Cliente": "IOC"}, "", false},
+	}
+	for _, test := range tests {
+		if got, ok := strategy.Category(CardCategoryInput{Fields: test.fields}); got != test.want || ok != test.ok {
+			t.Errorf("fields %v: got %q, %v; want %q, %v", test.fields, got, ok, test.want, test.ok)
+		}
+	}
+	if _, ok := (FieldStrategy{}).Category(CardCategoryInput{Fields: map[string]string{"": "meetings"}}); ok {
+		t.Error("a strategy without field should not categorize")
+	}
+}
+
+func TestCategorize(t *testing.T) {
+	input := CardCategoryInput{Name: "Slack thread with devops", Labels: []*trello.Label{{Name: "Urgent"}}}
+	if got := Categorize(CompositeStrategy{LabelStrategy{}, KeywordStrategy{}}, input); got != conversationsCategory {
+		t.Errorf("got %q, want the keywords category after the labels", got)
+	}
+	if got := Categorize(CompositeStrategy{LabelStrategy{}}, input); got != otherCategory {
+		t.Errorf("got %q, want Other when no strategy knows", got)
+	}
+}
+
+func TestCategorizationConfigStrategy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "categorization.json")
+	config := `{
+		"strategies": ["labels", "field", "keywords"],
+		"labels": {"names": {"Reunión": "meetings"}, "colors": {"purple": "conversations"}},
+		"field": "Categoría",
+		"boards": {
+			"Olympics": {"strategies": ["field", "labels"], "labels": {"colors": {"purple": "documentation"}}, "field": "Tipo"}
+		}
+	}`
+	if err := os.WriteFile(path, []byte(config), 0644); err != nil {
+		t.Fatalf("error writing config: %v", err)
+	}
+	loaded, err := LoadCategorizationConfig(path)
+	if err != nil {
+		t.Fatalf("error loading config: %v", err)
+	}
+
+	input := CardCategoryInput{
+		Name:   "Weekly call",
+		Labels: []*trello.Label{{Name: "Urgent", Color: "purple"}},
+		Fields: map[string]string{"Tipo": "implementation"},
+	}
+	tests := []struct {
+		board string
+		want  string
+	}{
+		{"LivGolf", conversationsCategory},
+		{"olympics", implementationCategory}, // The board field first
+	}
+	for _, test := range tests {
+		if got := Categorize(loaded.Strategy(test.board), input); got != test.want {
+			t.Errorf("board %s: got %q, want %q", test.board, got, test.want)
+		}
+	}
+
+	input.Fields = nil
+	if got := Categorize(loaded.Strategy("Olympics"), input); got != documentationCategory {
+		t.Errorf("got %q, want the board color overriding the global one", got)
+	}
+	input.Labels = []*trello.Label{{Name: "Reunión"}}
+	if got := Categorize(loaded.Strategy("Olympics"), input); got != meetingsCategory {
+		t.Errorf("got %q, want the global label names merged into the board ones", got)
+	}
+	if got := len((CategorizationConfig{}).Strategy("Olympics")); got != len(DefaultStrategies) {
+		t.Errorf("got %d strategies, want the defaults", got)
+	}
+}
+
+func TestCategorizationConfigValidate(t *testing.T) {
+	config := CategorizationConfig{Boards: map[string]CategorizationConfig{"Olympics": {Strategies: []string{"labels", "magic"}}}}
+	if err := config.Validate(); err == nil || !strings.Contains(err.Error(), `board Olympics: unknown categorization strategy "magic"`) {
+		t.Errorf("got %v, want the unknown strategy of the board", err)
+	}
+}
@@ -1,6 +1,35 @@
 package process
 
-import "strings"
+import (
+	"strings"
+	"sync"
+
+	"txeo-tools-library/models"
+)
+
+var (
+	defaultCategoryRegistry = models.DefaultCategoryRegistry()
+	categoryRegistryMu      sync.RWMutex
+	categoryRegistry        = defaultCategoryRegistry
+)
+
+// SetCategoryRegistry replaces the registry used to name and decorate
+// categories, nil restores the embedded defaults
+func SetCategoryRegistry(registry *models.CategoryRegistry) {
+	if registry == nil {
+		registry = defaultCategoryRegistry
+	}
+	categoryRegistryMu.Lock()
+	defer categoryRegistryMu.Unlock()
+	categoryRegistry = registry
+}
+
+// CategoryRegistry returns the registry used by the categorizer
+func CategoryRegistry() *models.CategoryRegistry {
+	categoryRegistryMu.RLock()
+	defer categoryRegistryMu.RUnlock()
+	return categoryRegistry
+}
 
 func GetTaskCategory(taskListName string) string {
 	taskListNameLower := strings.ToLower(taskListName)
@@ -127,7 +156,7 @@ func GetTaskCategory(taskListName string) string {
 	isBackfill := strings.Contains(taskListNameLower, "backfill")
 	switch {
 	case isCatchup || isMeeting || isCall || isExplaining || isSupporting || isSaturday || isSunday || isInvoice || isLIVX || isNeopoly || isCreatingSystem || isResponsive || isFantasy:
-		return "Catchups / Meetings"
+		return categoryName(models.CategoryMeetings)
 	case isTraining || isDataflow || isImplementation || isScreensets || isSpeaker || isCaptcha || isBackend || isGithub || isData || isDataFlow || isExtensions || isAPIKey || isBOTF || isEnvFiles || isIssues || isIssue || isPreferences || isFrontal ||
 		isEmarsys || isTemplating || isPoC || isDemo || isAdding || isPassword || isRecaptcha ||
 		isRegistrationCompletion || isTypescript || isReact || isArrays || isCLP || isStruct || isStructure ||
@@ -135,29 +164,32 @@ func GetTaskCategory(taskListName string) string {
 		isInvestigating || isSSO || isFixing || isTesting || isImport || isUseCase || isFix || isUsers || isLaunch || isChecks || isChecking ||
 		isTest || isOidc || isConfluence || isDocumentation || isTicket || isWeekly || isMail || isConsent || isSchema || isEnrollment || isKickoff || isAnswering ||
 		isNull || isRevert || isUpdate || isImproving || isPreparing || isRipper || isGenerate || isCatchupII || isCss || isEvents || isProblem || isInvestigate || isGoLive || isLogs ||
-		isDeletionProcess || isDeletion || isUserFlows || isLPC || isOIDC || isAPIKey || isBOTF || isEnvFiles || isPreferences || isFrontal || isGlances || isTasks || isMonitoring || isExport || isBlacklist || isCDC:
-		return "Implementation / Configuration tasks"
+		isDeletionProcess || isDeletion || isUserFlows || isLPC || isOIDC || isGlances || isTasks || isMonitoring || isExport || isBlacklist || isCDC:
+		return categoryName(models.CategoryImplementation)
 	case isEmail || isDocumentation || isConfluence || isDoc || isAnswer || isReport || isCss || isCNAME || isWebhooks || isCerts || isBackfields || isBackfill ||
 		isNextSteps || isIncidence:
-		return "Emails / Documentation"
+		return categoryName(models.CategoryDocumentation)
 	case isSlack || isTeams || isChat || isWeekly || isMail || isConsent || isSchema || isEnrollment || isKickoff || isAnswering || isAnswered || isMeeting ||
 		isExplaining || isHolidays || isDiscussion || isConversation || isConver || isDiscussing || isIssue || isIssues:
-		return "Slack / Teams Conversations"
+		return categoryName(models.CategoryConversations)
 	default:
-		return "Other"
+		return categoryName(models.CategoryOther)
 	}
 }
+
+// GetIconForCategory returns the icon of a category.
+//
+// Deprecated: use CategoryRegistry().Icon instead.
 func GetIconForCategory(category string) string {
-	switch category {
-	case "Catchups / Meetings":
-		return "📅"
-	case "Implementation / Configuration tasks":
-		return "💪"
-	case "Emails / Documentation":
-		return "📧"
-	case "Slack / Teams Conversations":
-		return "💬"
-	default:
-		return "❓"
+	return CategoryRegistry().Icon(category)
+}
+
+// categoryName resolves the name of a built-in category through the registry,
+// falling back to the embedded defaults when the registry does not know it.
+func categoryName(shortName string) string {
+	if category, ok := CategoryRegistry().GetByShortName(shortName); ok {
+		return category.Name
 	}
+	category, _ := defaultCategoryRegistry.GetByShortName(shortName)
+	return category.Name
 }
@@ -0,0 +1,152 @@
+package process
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"txeo-tools-library/models"
+)
+
+var update = flag.Bool("update", false, "regenerate the golden files")
+
+const (
+	categorizerCorpus = "categorizer_corpus.txt"
+	categorizerGolden = "categorizer.golden"
+)
+
+// TestGetTaskCategoryGolden classifies every task of the corpus and compares
+// the result with the golden file, so any rule change that reclassifies a
+// task is visible. Regenerate it with:
+//
+//	go test ./process -run TestGetTaskCategoryGolden -update
+func TestGetTaskCategoryGolden(t *testing.T) {
+	tasks := readCorpus(t, filepath.Join("testdata", categorizerCorpus))
+
+	var got []string
+	for _, task := range tasks {
+		got = append(got, task+"\t"+GetTaskCategory(task))
+	}
+
+	goldenPath := filepath.Join("testdata", categorizerGolden)
+	if *update {
+		if err := os.WriteFile(goldenPath, []byte(strings.Join(got, "\n")+"\n"), 0644); err != nil {
+			t.Fatalf("error writing golden file: %v", err)
+		}
+		return
+	}
+
+	data, err := os.ReadFile(goldenPath)
+	if err != nil {
+		t.Fatalf("error reading golden file, run with -update to create it: %v", err)
+	}
+	want := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+
+	if diff := diffLines(want, got); diff != "" {
+		t.Errorf("categories changed (- golden, + current), run with -update if the change is intended:\n%s", diff)
+	}
+}
+
+func readCorpus(t *testing.T, path string) []string {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("error opening corpus: %v", err)
+	}
+	defer file.Close()
+
+	var tasks []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		tasks = append(tasks, line)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("error reading corpus: %v", err)
+	}
+	return tasks
+}
+
+// diffLines compares "task\tcategory" lines by task, reporting changed,
+// missing and new tasks in a readable way.
+func diffLines(want, got []string) string {
+	wantByTask := map[string]string{}
+	for _, line := range want {
+		task, category, _ := strings.Cut(line, "\t")
+		wantByTask[task] = category
+	}
+
+	var b strings.Builder
+	seen := map[string]bool{}
+	for _, line := range got {
+		task, category, _ := strings.Cut(line, "\t")
+		seen[task] = true
+		wantCategory, ok := wantByTask[task]
+		switch {
+		case !ok:
+			fmt.Fprintf(&b, "+ %q → %s (not in golden file)\n", task, category)
+		case wantCategory != category:
+			fmt.Fprintf(&b, "  %q\n  - %s\n  + %s\n", task, wantCategory, category)
+		}
+	}
+	for _, line := range want {
+		task, category, _ := strings.Cut(line, "\t")
+		if !seen[task] {
+			fmt.Fprintf(&b, "- %q → %s (removed from corpus)\n", task, category)
+		}
+	}
+	return b.String()
+}
+
+func TestSetCategoryRegistry(t *testing.T) {
+	t.Cleanup(func() { SetCategoryRegistry(nil) })
+
+	renamed := models.DefaultCategoryRegistry().Categories()
+	for i := range renamed {
+		if renamed[i].ShortName == models.CategoryMeetings {
+			renamed[i].Name = "Meetings"
+		}
+	}
+	registry := models.NewCategoryRegistry(renamed)
+
+	// Replaced while other goroutines categorize, run with -race
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if category := GetTaskCategory("Weekly call"); category != "Meetings" && category != "Catchups / Meetings" {
+				t.Errorf("got category %q while replacing the registry", category)
+			}
+		}()
+	}
+	SetCategoryRegistry(registry)
+	wg.Wait()
+
+	if category := GetTaskCategory("Weekly call"); category != "Meetings" {
+		t.Errorf("got category %q, want the name of the new registry", category)
+	}
+	SetCategoryRegistry(nil)
+	if category := GetTaskCategory("Weekly call"); category != "Catchups / Meetings" {
+		t.Errorf("got category %q, want the default name back", category)
+	}
+}
+
+func TestGetCategoriesKeepsTheFourCategories(t *testing.T) {
+	var names []string
+	for _, category := range (models.Categories{}).GetCategories() {
+		names = append(names, category.Icon+" "+category.Name)
+	}
+	want := []string{"📅 Catchups / Meetings", "💪 Implementation / Configuration tasks", "📧 Emails / Documentation", "💬 Slack / Teams Conversations"}
+	if strings.Join(names, "|") != strings.Join(want, "|") {
+		t.Errorf("got categories %q, want %q", names, want)
+	}
+}
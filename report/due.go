@@ -0,0 +1,309 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	ttrello "txeo-tools-library/trello"
+
+	"github.com/adlio/trello"
+)
+
+// Reasons a card shows up in the due report
+const (
+	DueOverdue    = "overdue"
+	DueSoon       = "due-soon"
+	DueStale      = "stale"
+	DueUnassigned = "unassigned"
+)
+
+// DueOptions sets which cards the due report flags
+type DueOptions struct {
+	DueWithin  int       // Days ahead to flag due dates, only overdue cards when 0
+	StaleAfter int       // Days without activity to flag a card as stale, disabled when 0
+	Unassigned bool      // Flag the cards without members
+	SkipLists  []string  // Names of the lists not scanned, like "Done"
+	Now        time.Time // time.Now() when zero
+}
+
+// DueCard is a flagged card with the reasons it was flagged
+type DueCard struct {
+	ID           string     `json:"id"`
+	Name         string     `json:"name"`
+	URL          string     `json:"url,omitempty"`
+	Due          *time.Time `json:"due,omitempty"`
+	LastActivity *time.Time `json:"lastActivity,omitempty"`
+	Members      []string   `json:"members,omitempty"`
+	Issues       []string   `json:"issues"`
+}
+
+// DueList holds the flagged cards of a list
+type DueList struct {
+	ID    string    `json:"id"`
+	Name  string    `json:"name"`
+	Cards []DueCard `json:"cards"`
+}
+
+// DueBoard holds the lists of a board with flagged cards
+type DueBoard struct {
+	ID    string    `json:"id"`
+	Name  string    `json:"name"`
+	Cards int       `json:"cards"`
+	Lists []DueList `json:"lists"`
+}
+
+// DueReport is the overdue, due soon, stale and unassigned cards of some boards
+type DueReport struct {
+	GeneratedAt time.Time      `json:"generatedAt"`
+	DueWithin   int            `json:"dueWithin"`
+	StaleAfter  int            `json:"staleAfter,omitempty"`
+	Boards      []DueBoard     `json:"boards"`
+	Totals      map[string]int `json:"totals"` // Flagged cards by reason
+}
+
+// GetDueReport scans the open cards of the open lists of every board, in the
+// given order, and groups the flagged ones by board and list. Cards with the
+// due date marked complete are never overdue nor due soon. A failing board
+// doesn't stop the others: the report holds the boards that loaded and the
+// error, a ttrello.FetchErrors, lists what failed.
+func GetDueReport(src ttrello.Source, boardIDs []string, options DueOptions) (DueReport, error) {
+	now := options.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	report := DueReport{GeneratedAt: now, DueWithin: options.DueWithin, StaleAfter: options.StaleAfter, Totals: map[string]int{}}
+	fetchErrors := ttrello.FetchErrors{}
+
+	for _, boardID := range boardIDs {
+		board, err := getDueBoard(src, boardID, now, options)
+		if err != nil {
+			fetchErrors[boardID] = append(fetchErrors[boardID], err)
+			continue
+		}
+		for _, list := range board.Lists {
+			for _, card := range list.Cards {
+				for _, issue := range card.Issues {
+					report.Totals[issue]++
+				}
+			}
+		}
+		report.Boards = append(report.Boards, board)
+	}
+	if len(fetchErrors) > 0 {
+		return report, fetchErrors
+	}
+	return report, nil
+}
+
+// CardIssues returns the reasons to flag a card at now
+func CardIssues(card *trello.Card, now time.Time, options DueOptions) []string {
+	var issues []string
+	if card.Due != nil && !card.DueComplete {
+		switch {
+		case card.Due.Before(now):
+			issues = append(issues, DueOverdue)
+		case !card.Due.After(now.AddDate(0, 0, options.DueWithin)):
+			issues = append(issues, DueSoon)
+		}
+	}
+	if options.StaleAfter > 0 && card.DateLastActivity != nil && card.DateLastActivity.Before(now.AddDate(0, 0, -options.StaleAfter)) {
+		issues = append(issues, DueStale)
+	}
+	if options.Unassigned && len(card.IDMembers) == 0 {
+		issues = append(issues, DueUnassigned)
+	}
+	return issues
+}
+
+// Table renders the due report for the terminal
+func (r DueReport) Table() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Due report - %s\n", r.GeneratedAt.Format("2006-01-02 15:04"))
+
+	for _, board := range r.Boards {
+		fmt.Fprintf(&b, "\n%s (%d)\n", board.Name, board.Cards)
+		if board.Cards == 0 {
+			fmt.Fprintf(&b, "  ✅ Nothing to review\n")
+			continue
+		}
+		for _, list := range board.Lists {
+			fmt.Fprintf(&b, "  %s\n", list.Name)
+			for _, card := range list.Cards {
+				fmt.Fprintf(&b, "    · %s - %s", card.Name, strings.Join(r.describeIssues(card), ", "))
+				if len(card.Members) > 0 {
+					fmt.Fprintf(&b, " (%s)", strings.Join(card.Members, ", "))
+				}
+				fmt.Fprintf(&b, "\n")
+			}
+		}
+	}
+
+	fmt.Fprintf(&b, "\n%s\n", strings.Repeat("─", 40))
+	fmt.Fprintf(&b, "TOTAL: %s\n", r.totalsLine())
+	return b.String()
+}
+
+// Markdown renders the due report with a table per list
+func (r DueReport) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Due report - %s\n\n", r.GeneratedAt.Format("2006-01-02"))
+	fmt.Fprintf(&b, "**Total:** %s\n", r.totalsLine())
+
+	for _, board := range r.Boards {
+		fmt.Fprintf(&b, "\n## %s\n", board.Name)
+		if board.Cards == 0 {
+			fmt.Fprintf(&b, "\nNothing to review.\n")
+			continue
+		}
+		for _, list := range board.Lists {
+			fmt.Fprintf(&b, "\n### %s\n\n", list.Name)
+			fmt.Fprintf(&b, "| Card | Due | Last activity | Members | Issues |\n")
+			fmt.Fprintf(&b, "| --- | --- | --- | --- | --- |\n")
+			for _, card := range list.Cards {
+				name := escapeMarkdown(card.Name)
+				if card.URL != "" {
+					name = fmt.Sprintf("[%s](%s)", name, card.URL)
+				}
+				fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n", name, formatDay(card.Due), formatDay(card.LastActivity),
+					escapeMarkdown(strings.Join(card.Members, ", ")), strings.Join(r.describeIssues(card), ", "))
+			}
+		}
+	}
+	return b.String()
+}
+
+// JSON renders the due report as indented JSON
+func (r DueReport) JSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+/* ╭──────────────────────────────────────────╮ */
+/* │              AUX FUNCTIONS               │ */
+/* ╰──────────────────────────────────────────╯ */
+func getDueBoard(src ttrello.Source, boardID string, now time.Time, options DueOptions) (DueBoard, error) {
+	board, err := src.GetBoard(boardID)
+	if err != nil {
+		return DueBoard{}, fmt.Errorf("error fetching board %s: %w", boardID, err)
+	}
+	lists, err := src.GetLists(boardID)
+	if err != nil {
+		return DueBoard{}, fmt.Errorf("error fetching lists of board %s: %w", board.Name, err)
+	}
+	cards, err := src.GetBoardCards(boardID)
+	if err != nil {
+		return DueBoard{}, fmt.Errorf("error fetching cards of board %s: %w", board.Name, err)
+	}
+
+	// Member names when the source knows them, the IDs otherwise
+	memberNames := map[string]string{}
+	if members, ok := src.(ttrello.MemberSource); ok {
+		boardMembers, err := members.GetMembers(boardID)
+		if err != nil {
+			return DueBoard{}, fmt.Errorf("error fetching members of board %s: %w", board.Name, err)
+		}
+		for _, member := range boardMembers {
+			memberNames[member.ID] = member.FullName
+		}
+	}
+
+	sort.SliceStable(lists, func(i, j int) bool { return lists[i].Pos < lists[j].Pos })
+	byList := map[string]*DueList{}
+	var order []string
+	for _, list := range lists {
+		if list.Closed || skipList(list.Name, options.SkipLists) {
+			continue
+		}
+		byList[list.ID] = &DueList{ID: list.ID, Name: list.Name}
+		order = append(order, list.ID)
+	}
+
+	result := DueBoard{ID: board.ID, Name: board.Name}
+	for _, card := range cards {
+		list, ok := byList[card.IDList]
+		if !ok || card.Closed {
+			continue
+		}
+		issues := CardIssues(card, now, options)
+		if len(issues) == 0 {
+			continue
+		}
+		dueCard := DueCard{ID: card.ID, Name: card.Name, URL: card.ShortURL, Due: card.Due, LastActivity: card.DateLastActivity, Issues: issues}
+		for _, memberID := range card.IDMembers {
+			if name := memberNames[memberID]; name != "" {
+				memberID = name
+			}
+			dueCard.Members = append(dueCard.Members, memberID)
+		}
+		list.Cards = append(list.Cards, dueCard)
+		result.Cards++
+	}
+
+	for _, listID := range order {
+		list := byList[listID]
+		if len(list.Cards) == 0 {
+			continue
+		}
+		sort.SliceStable(list.Cards, func(i, j int) bool { return dueBefore(list.Cards[i], list.Cards[j]) })
+		result.Lists = append(result.Lists, *list)
+	}
+	return result, nil
+}
+
+// dueBefore sorts the cards by due date, the ones without due date last
+func dueBefore(a, b DueCard) bool {
+	if a.Due == nil || b.Due == nil {
+		return a.Due != nil && b.Due == nil
+	}
+	return a.Due.Before(*b.Due)
+}
+
+func skipList(name string, skip []string) bool {
+	for _, skipped := range skip {
+		if strings.EqualFold(strings.TrimSpace(skipped), strings.TrimSpace(name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// describeIssues explains the issues of a card relative to the report date
+func (r DueReport) describeIssues(card DueCard) []string {
+	var descriptions []string
+	for _, issue := range card.Issues {
+		switch issue {
+		case DueOverdue:
+			descriptions = append(descriptions, fmt.Sprintf("⏰ overdue %s", days(r.GeneratedAt.Sub(*card.Due))))
+		case DueSoon:
+			descriptions = append(descriptions, fmt.Sprintf("📅 due in %s", days(card.Due.Sub(r.GeneratedAt))))
+		case DueStale:
+			descriptions = append(descriptions, fmt.Sprintf("💤 idle %s", days(r.GeneratedAt.Sub(*card.LastActivity))))
+		case DueUnassigned:
+			descriptions = append(descriptions, "👤 unassigned")
+		default:
+			descriptions = append(descriptions, issue)
+		}
+	}
+	return descriptions
+}
+
+func (r DueReport) totalsLine() string {
+	return fmt.Sprintf("%d overdue, %d due soon, %d stale, %d unassigned",
+		r.Totals[DueOverdue], r.Totals[DueSoon], r.Totals[DueStale], r.Totals[DueUnassigned])
+}
+
+func days(d time.Duration) string {
+	if d < 24*time.Hour {
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dd", int(d.Hours()/24))
+}
+
+func formatDay(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Format("2006-01-02")
+}
@@ -0,0 +1,112 @@
+package report
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	ttrello "txeo-tools-library/trello"
+
+	"github.com/adlio/trello"
+)
+
+const dueBoardID = "617c56690fcb27430e740522"
+
+func TestCardIssuesBoundaries(t *testing.T) {
+	now := time.Date(2024, 10, 24, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		t := now.Add(d)
+		return &t
+	}
+	day := 24 * time.Hour
+	assigned := []string{"5a1b2c3d4e5f60718293a4b5"}
+
+	tests := []struct {
+		name    string
+		card    trello.Card
+		options DueOptions
+		want    []string
+	}{
+		{"due a moment ago", trello.Card{Due: at(-time.Nanosecond), IDMembers: assigned}, DueOptions{}, []string{DueOverdue}},
+		{"due right now", trello.Card{Due: at(0), IDMembers: assigned}, DueOptions{}, []string{DueSoon}},
+		{"due later without window", trello.Card{Due: at(time.Minute), IDMembers: assigned}, DueOptions{}, nil},
+		{"due at the end of the window", trello.Card{Due: at(3 * day), IDMembers: assigned}, DueOptions{DueWithin: 3}, []string{DueSoon}},
+		{"due after the window", trello.Card{Due: at(3*day + time.Nanosecond), IDMembers: assigned}, DueOptions{DueWithin: 3}, nil},
+		{"overdue but complete", trello.Card{Due: at(-day), DueComplete: true, IDMembers: assigned}, DueOptions{DueWithin: 3}, nil},
+		{"idle for exactly the limit", trello.Card{DateLastActivity: at(-7 * day), IDMembers: assigned}, DueOptions{StaleAfter: 7}, nil},
+		{"idle past the limit", trello.Card{DateLastActivity: at(-7*day - time.Second), IDMembers: assigned}, DueOptions{StaleAfter: 7}, []string{DueStale}},
+		{"idle without limit", trello.Card{DateLastActivity: at(-365 * day), IDMembers: assigned}, DueOptions{}, nil},
+		{"unassigned", trello.Card{}, DueOptions{Unassigned: true}, []string{DueUnassigned}},
+		{"everything", trello.Card{Due: at(-day), DateLastActivity: at(-30 * day)}, DueOptions{StaleAfter: 7, Unassigned: true}, []string{DueOverdue, DueStale, DueUnassigned}},
+	}
+	for _, test := range tests {
+		if got := CardIssues(&test.card, now, test.options); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestGetDueReport(t *testing.T) {
+	src := newFakeSource(t)
+
+	options := DueOptions{
+		DueWithin:  2,
+		StaleAfter: 14,
+		Unassigned: true,
+		SkipLists:  []string{"doing"},
+		Now:        time.Date(2024, 10, 24, 12, 0, 0, 0, time.UTC),
+	}
+	report, err := GetDueReport(src, []string{dueBoardID}, options)
+	if err != nil {
+		t.Fatalf("error building the report: %v", err)
+	}
+
+	type flagged struct {
+		list, card string
+		issues     []string
+		members    []string
+	}
+	want := []flagged{
+		{"Octubre 2024", "Documentation for the handover 1h30", []string{DueSoon}, []string{"Txeo"}},
+		{"Octubre 2024", "Weekly call with IOC team", []string{DueStale}, []string{"Txeo"}},
+		{"Octubre 2024", "Fix login redirect (2h)", []string{DueStale, DueUnassigned}, nil},
+		{"Noviembre 2024", "Slack thread with devops", []string{DueUnassigned}, nil},
+		{"Noviembre 2024", "Checkout flow fixes", []string{DueUnassigned}, nil},
+	}
+	var got []flagged
+	for _, board := range report.Boards {
+		for _, list := range board.Lists {
+			for _, card := range list.Cards {
+				got = append(got, flagged{list.Name, card.Name, card.Issues, card.Members})
+			}
+		}
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got cards\n%+v\nwant\n%+v", got, want)
+	}
+	if totals := map[string]int{DueSoon: 1, DueStale: 2, DueUnassigned: 3}; !reflect.DeepEqual(report.Totals, totals) {
+		t.Errorf("got totals %v, want %v", report.Totals, totals)
+	}
+
+	table := report.Table()
+	for _, line := range []string{"📅 due in 1d", "💤 idle 21d", "TOTAL: 0 overdue, 1 due soon, 2 stale, 3 unassigned"} {
+		if !strings.Contains(table, line) {
+			t.Errorf("table misses %q:\n%s", line, table)
+		}
+	}
+}
+
+func TestGetDueReportSkipsFailingBoards(t *testing.T) {
+	const missingBoardID = "66462e4bca554f21f82b8e4a"
+	report, err := GetDueReport(newFakeSource(t), []string{missingBoardID, dueBoardID}, DueOptions{Now: time.Date(2024, 10, 24, 12, 0, 0, 0, time.UTC)})
+
+	var fetchErrors ttrello.FetchErrors
+	if !errors.As(err, &fetchErrors) || len(fetchErrors) != 1 || len(fetchErrors[missingBoardID]) != 1 {
+		t.Fatalf("got error %v, want a FetchErrors of the missing board", err)
+	}
+	if len(report.Boards) != 1 || report.Boards[0].Name != "Olympics" {
+		t.Errorf("got boards %+v, want the Olympics board that loaded", report.Boards)
+	}
+}
@@ -0,0 +1,126 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"txeo-tools-library/tools"
+)
+
+// Table renders the report as a plain text table for the terminal
+func (r Report) Table() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s - %s\n\n", r.Board, r.Period())
+
+	width := displayWidth("TOTAL")
+	for _, category := range r.Categories {
+		width = tools.Max(width, displayWidth(category.Icon+" "+category.DisplayName))
+	}
+
+	fmt.Fprintf(&b, "%s  %6s  %8s  %7s\n", pad("CATEGORY", width), "TASKS", "HOURS", "%")
+	fmt.Fprintf(&b, "%s\n", strings.Repeat("─", width+27))
+	for _, category := range r.Categories {
+		fmt.Fprintf(&b, "%s  %6d  %8.2f  %7.2f\n", pad(category.Icon+" "+category.DisplayName, width), category.Count, category.Hours, category.Percentage)
+		for _, task := range category.Tasks {
+			fmt.Fprintf(&b, "    · %s (%.2fh)\n", task.Name, task.TimeForTask)
+		}
+	}
+	fmt.Fprintf(&b, "%s\n", strings.Repeat("─", width+27))
+	fmt.Fprintf(&b, "%s  %6d  %8.2f  %7.2f\n", pad("TOTAL", width), r.TotalTasks, r.TotalHours, totalPercentage(r))
+
+	if len(r.Warnings) > 0 {
+		fmt.Fprintf(&b, "\n⚠️  Warnings:\n")
+		for _, warning := range r.Warnings {
+			fmt.Fprintf(&b, "  - %s\n", warning)
+		}
+	}
+	return b.String()
+}
+
+// Markdown renders the report as a Markdown document
+func (r Report) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s - %s\n\n", r.Board, r.Period())
+	fmt.Fprintf(&b, "| Category | Tasks | Hours | %% |\n")
+	fmt.Fprintf(&b, "| --- | ---: | ---: | ---: |\n")
+	for _, category := range r.Categories {
+		fmt.Fprintf(&b, "| %s %s | %d | %.2f | %.2f |\n", category.Icon, escapeMarkdown(category.DisplayName), category.Count, category.Hours, category.Percentage)
+	}
+	fmt.Fprintf(&b, "| **Total** | **%d** | **%.2f** | **%.2f** |\n", r.TotalTasks, r.TotalHours, totalPercentage(r))
+
+	for _, category := range r.Categories {
+		fmt.Fprintf(&b, "\n## %s %s\n\n", category.Icon, category.DisplayName)
+		for _, task := range category.Tasks {
+			fmt.Fprintf(&b, "- %s (%.2fh)\n", task.Name, task.TimeForTask)
+		}
+	}
+
+	if len(r.Warnings) > 0 {
+		fmt.Fprintf(&b, "\n## Warnings\n\n")
+		for _, warning := range r.Warnings {
+			fmt.Fprintf(&b, "- %s\n", warning)
+		}
+	}
+	return b.String()
+}
+
+// CSV renders one row per task with its category
+func (r Report) CSV() string {
+	header := []string{"Board", "Month", "Year", "Category", "Task", "Hours"}
+	var rows [][]string
+	for _, category := range r.Categories {
+		for _, task := range category.Tasks {
+			rows = append(rows, []string{r.Board, r.Month, r.Year, category.Name, task.Name, fmt.Sprintf("%.2f", task.TimeForTask)})
+		}
+	}
+	return tools.ToCSV(header, rows)
+}
+
+// SummaryCSV renders one row per category with its totals
+func (r Report) SummaryCSV() string {
+	header := []string{"Board", "Month", "Year", "Category", "Tasks", "Hours", "Percentage"}
+	var rows [][]string
+	for _, category := range r.Categories {
+		rows = append(rows, []string{r.Board, r.Month, r.Year, category.Name, fmt.Sprint(category.Count), fmt.Sprintf("%.2f", category.Hours), fmt.Sprintf("%.2f", category.Percentage)})
+	}
+	return tools.ToCSV(header, rows)
+}
+
+// JSON renders the report as indented JSON
+func (r Report) JSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+/* ╭──────────────────────────────────────────╮ */
+/* │              AUX FUNCTIONS               │ */
+/* ╰──────────────────────────────────────────╯ */
+func pad(s string, width int) string {
+	return s + strings.Repeat(" ", tools.Max(0, width-displayWidth(s)))
+}
+
+// displayWidth counts the terminal columns of s, emojis take two
+func displayWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		switch {
+		case r == '\uFE0F' || r == '\u200D':
+		case r >= 0x1F000 || (r >= 0x2600 && r <= 0x27BF):
+			width += 2
+		default:
+			width++
+		}
+	}
+	return width
+}
+
+func totalPercentage(r Report) float64 {
+	if len(r.Categories) == 0 {
+		return 0
+	}
+	return 100
+}
+
+func escapeMarkdown(s string) string {
+	return strings.ReplaceAll(s, "|", "\\|")
+}
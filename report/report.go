@@ -0,0 +1,173 @@
+package report
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"txeo-tools-library/models"
+	"txeo-tools-library/process"
+	"txeo-tools-library/source"
+	ttrello "txeo-tools-library/trello"
+
+	"github.com/adlio/trello"
+)
+
+// CategoryReport holds the tasks and totals of a single category
+type CategoryReport struct {
+	Name        string        `json:"name"`
+	DisplayName string        `json:"displayName"` // Translated name when the registry is localized
+	Icon        string        `json:"icon"`
+	Color       string        `json:"color,omitempty"`
+	Hours       float64       `json:"hours"`
+	Count       int           `json:"count"`
+	Percentage  float64       `json:"percentage"` // Share of the total hours (or of the tasks when there are no hours)
+	Tasks       []models.Task `json:"tasks"`
+}
+
+// Report is the time report of a board for a month
+type Report struct {
+	Board      string           `json:"board"`
+	Month      string           `json:"month"`
+	Year       string           `json:"year,omitempty"`
+	TotalHours float64          `json:"totalHours"`
+	TotalTasks int              `json:"totalTasks"`
+	Categories []CategoryReport `json:"categories"`
+	Warnings   []string         `json:"warnings,omitempty"`
+}
+
+// Options tunes how cards are turned into tasks
+type Options struct {
+	HoursField string                   // Name of the custom field holding the hours of a card, if any
+	Registry   *models.CategoryRegistry // Category metadata, process.CategoryRegistry() when nil
+	Strategy   process.CategoryStrategy // Categorizes the cards by labels or custom fields, by name keywords when nil
+}
+
+// GetMonthlyReport fetches the cards of the month list of a board and builds its report
+func GetMonthlyReport(client *trello.Client, board *trello.Board, month models.Month, year models.Year, options Options) (Report, error) {
+	return GetMonthlyReportFromSource(ttrello.NewAPISource(client), board.ID, month, year, options)
+}
+
+// GetMonthlyReportFromSource builds the report of a board reading from src,
+// which may be the live API, the local cache or a snapshot
+func GetMonthlyReportFromSource(src ttrello.Source, boardID string, month models.Month, year models.Year, options Options) (Report, error) {
+	board, err := src.GetBoard(boardID)
+	if err != nil {
+		return Report{}, fmt.Errorf("error fetching board %s: %w", boardID, err)
+	}
+	trelloSource := source.NewTrelloSource(src, []ttrello.BoardInfo{{ID: board.ID, Name: board.Name}},
+		source.TrelloOptions{HoursField: options.HoursField, Strategy: options.Strategy})
+
+	if !month.IsAllYear() && year.Number() != 0 {
+		report, err := GetTaskSourceReport(trelloSource, board.ID, source.MonthPeriod(year.Number(), month.Time), options.Registry)
+		if err != nil {
+			return Report{}, err
+		}
+		report.Month = month.Name
+		return report, nil
+	}
+
+	tasks, warnings, err := trelloSource.MonthTasks(board.ID, month, year)
+	if err != nil {
+		return Report{}, err
+	}
+	report := BuildReportWithRegistry(board.Name, month.Name, tasks, options.Registry)
+	report.Year = year.Name
+	report.Warnings = warnings
+	return report, nil
+}
+
+// GetTaskSourceReport builds the report of a project of any task source.
+// Tasks the source didn't categorize are categorized by their name keywords.
+func GetTaskSourceReport(src source.TaskSource, projectID string, period source.Period, registry *models.CategoryRegistry) (Report, error) {
+	project, err := source.FindProject(src, projectID)
+	if err != nil {
+		return Report{}, err
+	}
+	tasks, warnings, err := src.Tasks(project.ID, period)
+	if err != nil {
+		return Report{}, err
+	}
+
+	month, year := period.Name, ""
+	if period.IsMonth() {
+		month, year = period.Start.Month().String(), strconv.Itoa(period.Start.Year())
+	}
+	report := BuildReportWithRegistry(project.Name, month, tasks, registry)
+	report.Year = year
+	report.Warnings = warnings
+	return report, nil
+}
+
+// BuildReport groups already categorized tasks and computes the totals of each category
+func BuildReport(board, month string, tasks []models.Task) Report {
+	return BuildReportWithRegistry(board, month, tasks, nil)
+}
+
+// BuildReportWithRegistry is BuildReport taking icons, colors and names from registry
+func BuildReportWithRegistry(board, month string, tasks []models.Task, registry *models.CategoryRegistry) Report {
+	if registry == nil {
+		registry = process.CategoryRegistry()
+	}
+	report := Report{Board: board, Month: month}
+
+	byCategory := map[string]*CategoryReport{}
+	for _, task := range tasks {
+		category := task.Category
+		if category == "" {
+			category = process.GetTaskCategory(task.Name)
+		}
+		categoryReport, ok := byCategory[category]
+		if !ok {
+			categoryReport = &CategoryReport{
+				Name:        category,
+				DisplayName: registry.DisplayName(category),
+				Icon:        registry.Icon(category),
+				Color:       registry.Color(category),
+			}
+			byCategory[category] = categoryReport
+		}
+		categoryReport.Tasks = append(categoryReport.Tasks, task)
+		categoryReport.Count++
+		categoryReport.Hours += task.TimeForTask
+
+		report.TotalTasks++
+		report.TotalHours += task.TimeForTask
+	}
+
+	for _, categoryReport := range byCategory {
+		categoryReport.Hours = round(categoryReport.Hours)
+		if report.TotalHours > 0 {
+			categoryReport.Percentage = round(categoryReport.Hours * 100 / report.TotalHours)
+		} else if report.TotalTasks > 0 {
+			categoryReport.Percentage = round(float64(categoryReport.Count) * 100 / float64(report.TotalTasks))
+		}
+		report.Categories = append(report.Categories, *categoryReport)
+	}
+	report.TotalHours = round(report.TotalHours)
+
+	sort.SliceStable(report.Categories, func(i, j int) bool {
+		if report.Categories[i].Hours != report.Categories[j].Hours {
+			return report.Categories[i].Hours > report.Categories[j].Hours
+		}
+		if report.Categories[i].Count != report.Categories[j].Count {
+			return report.Categories[i].Count > report.Categories[j].Count
+		}
+		return report.Categories[i].Name < report.Categories[j].Name
+	})
+
+	return report
+}
+
+// Period is the month and year of the report as shown in titles
+func (r Report) Period() string {
+	if r.Year == "" {
+		return r.Month
+	}
+	return r.Month + " " + r.Year
+}
+
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,148 @@
+package report
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"txeo-tools-library/models"
+	"txeo-tools-library/source"
+	ttrello "txeo-tools-library/trello"
+)
+
+const (
+	meetings       = "Catchups / Meetings"
+	implementation = "Implementation / Configuration tasks"
+	documentation  = "Emails / Documentation"
+	conversations  = "Slack / Teams Conversations"
+)
+
+type categoryTotals struct {
+	Name       string
+	Hours      float64
+	Count      int
+	Percentage float64
+}
+
+func totals(report Report) []categoryTotals {
+	var got []categoryTotals
+	for _, category := range report.Categories {
+		got = append(got, categoryTotals{category.Name, category.Hours, category.Count, category.Percentage})
+	}
+	return got
+}
+
+func TestBuildReport(t *testing.T) {
+	tasks := []models.Task{
+		{Name: "Weekly call", Category: meetings, TimeForTask: 1},
+		{Name: "Fix login redirect", Category: implementation, TimeForTask: 2.5},
+		{Name: "Sprint planning", Category: meetings, TimeForTask: 1.5},
+		{Name: "Doc review", TimeForTask: 1}, // Categorized by its name
+		{Name: "Slack thread", Category: conversations, TimeForTask: 0},
+	}
+	report := BuildReport("Olympics", "October", tasks)
+
+	want := []categoryTotals{
+		{meetings, 2.5, 2, 41.67},
+		{implementation, 2.5, 1, 41.67},
+		{documentation, 1, 1, 16.67},
+		{conversations, 0, 1, 0},
+	}
+	if got := totals(report); !reflect.DeepEqual(got, want) {
+		t.Errorf("got categories\n%+v\nwant\n%+v", got, want)
+	}
+	if report.TotalHours != 6 || report.TotalTasks != 5 {
+		t.Errorf("got %.2fh in %d tasks, want 6h in 5", report.TotalHours, report.TotalTasks)
+	}
+	if icon := report.Categories[1].Icon; icon != "💪" {
+		t.Errorf("got icon %q for %s", icon, implementation)
+	}
+}
+
+func TestBuildReportWithoutHours(t *testing.T) {
+	tasks := []models.Task{
+		{Name: "Weekly call", Category: meetings},
+		{Name: "Sprint planning", Category: meetings},
+		{Name: "Fix login redirect", Category: implementation},
+	}
+	report := BuildReport("Olympics", "October", tasks)
+
+	want := []categoryTotals{
+		{meetings, 0, 2, 66.67},
+		{implementation, 0, 1, 33.33},
+	}
+	if got := totals(report); !reflect.DeepEqual(got, want) {
+		t.Errorf("got categories\n%+v\nwant\n%+v", got, want)
+	}
+	if empty := BuildReport("Olympics", "October", nil); len(empty.Categories) != 0 || empty.TotalHours != 0 {
+		t.Errorf("got %+v for no tasks", empty)
+	}
+}
+
+func TestGetMonthlyReportFromSource(t *testing.T) {
+	src := newFakeSource(t)
+
+	month := models.Months{}.GetMonths().GetMonthByName("October")
+	year := models.Year{}.GetYears()[0]
+	report, err := GetMonthlyReportFromSource(src, dueBoardID, month, year, Options{HoursField: "Horas"})
+	if err != nil {
+		t.Fatalf("error building the report: %v", err)
+	}
+
+	if report.Board != "Olympics" || report.Period() != "October 2024" {
+		t.Errorf("got report of %s for %s", report.Board, report.Period())
+	}
+	if report.TotalTasks != 3 || report.TotalHours != 5 {
+		t.Errorf("got %.2fh in %d tasks, want the 5h of the 3 cards of October", report.TotalHours, report.TotalTasks)
+	}
+	var percentage float64
+	for _, category := range report.Categories {
+		percentage += category.Percentage
+	}
+	if percentage < 99.98 || percentage > 100.02 {
+		t.Errorf("percentages add up to %.2f, want 100: %+v", percentage, totals(report))
+	}
+}
+
+func TestGetTaskSourceReport(t *testing.T) {
+	boards := []ttrello.BoardInfo{{ID: dueBoardID, Name: "Olympics"}}
+	src := source.NewTrelloSource(newFakeSource(t), boards, source.TrelloOptions{HoursField: "Horas"})
+
+	report, err := GetTaskSourceReport(src, "Olympics", source.MonthPeriod(2024, time.October), nil)
+	if err != nil {
+		t.Fatalf("error building the report: %v", err)
+	}
+
+	if report.Board != "Olympics" || report.Period() != "October 2024" {
+		t.Errorf("got report of %s for %s", report.Board, report.Period())
+	}
+	if report.TotalTasks != 3 || report.TotalHours != 5 {
+		t.Errorf("got %.2fh in %d tasks, want the 5h of the 3 cards of October", report.TotalHours, report.TotalTasks)
+	}
+	var hours float64
+	for _, category := range report.Categories {
+		hours += category.Hours
+	}
+	if hours != report.TotalHours {
+		t.Errorf("categories add up to %.2fh, want %.2fh: %+v", hours, report.TotalHours, totals(report))
+	}
+}
+
+// newFakeSource reads the fixtures of the trello package through a fake server
+func newFakeSource(t *testing.T) ttrello.Source {
+	t.Helper()
+	fixtures, err := ttrello.LoadFixtures(filepath.Join("..", "trello", "testdata", "fake"))
+	if err != nil {
+		t.Fatalf("error loading fixtures: %v", err)
+	}
+	fake := ttrello.NewFakeServer(fixtures)
+	t.Cleanup(fake.Close)
+	config := fake.Config()
+	config.Boards.CachePath = filepath.Join(t.TempDir(), "boards.json")
+	client, err := ttrello.New(config)
+	if err != nil {
+		t.Fatalf("error creating client: %v", err)
+	}
+	return ttrello.NewAPISource(client.API)
+}
@@ -0,0 +1,210 @@
+package source
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"txeo-tools-library/models"
+	"txeo-tools-library/process"
+)
+
+// NameGitHub is the name of the GitHub source
+const NameGitHub = "github"
+
+// DefaultGitHubURL is the base URL of the GitHub REST API
+const DefaultGitHubURL = "https://api.github.com"
+
+const githubPageSize = 100
+
+// GitHubConfig tells GitHubSource which repositories to read and how
+type GitHubConfig struct {
+	BaseURL      string                   // DefaultGitHubURL when empty
+	Token        string                   // Personal access token, optional for public repositories
+	Repos        []string                 // Repositories as owner/name
+	Client       string                   // Client the repositories are billed to, if any
+	PullRequests bool                     // Include merged pull requests besides issues
+	Strategy     process.CategoryStrategy // Categorizes the issues by labels, by title keywords when nil
+	HTTPClient   *http.Client             // http.DefaultClient when nil
+}
+
+// GitHubSource reads the issues closed (and pull requests merged) in a period.
+// The time of an issue is taken from a label like "3h" or "estimate: 1h30",
+// or from its title annotation, as there is no time tracking in GitHub.
+type GitHubSource struct {
+	config GitHubConfig
+}
+
+// NewGitHubSource reads the repositories of config
+func NewGitHubSource(config GitHubConfig) *GitHubSource {
+	if config.BaseURL == "" {
+		config.BaseURL = DefaultGitHubURL
+	}
+	config.BaseURL = strings.TrimRight(config.BaseURL, "/")
+	return &GitHubSource{config: config}
+}
+
+// githubIssue is the part of an issue (or pull request) of the REST API we use
+type githubIssue struct {
+	Number      int       `json:"number"`
+	Title       string    `json:"title"`
+	HTMLURL     string    `json:"html_url"`
+	State       string    `json:"state"`
+	StateReason string    `json:"state_reason"` // completed, not_planned or reopened
+	CreatedAt   time.Time `json:"created_at"`
+	ClosedAt    time.Time `json:"closed_at"`
+	Labels      []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	PullRequest *struct {
+		MergedAt *time.Time `json:"merged_at"`
+	} `json:"pull_request"`
+}
+
+func (s *GitHubSource) Name() string {
+	return NameGitHub
+}
+
+func (s *GitHubSource) Projects() ([]Project, error) {
+	projects := make([]Project, 0, len(s.config.Repos))
+	for _, repo := range s.config.Repos {
+		projects = append(projects, Project{ID: repo, Name: repo, Source: NameGitHub})
+	}
+	return projects, nil
+}
+
+// Periods returns the months some issue of the repository was closed in
+func (s *GitHubSource) Periods(projectID string) ([]Period, error) {
+	issues, err := s.closedIssues(projectID, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	var periods []Period
+	for _, issue := range issues {
+		if !s.billable(issue) {
+			continue
+		}
+		period := MonthPeriod(issue.ClosedAt.Year(), issue.ClosedAt.Month())
+		if !seen[period.Key()] {
+			seen[period.Key()] = true
+			periods = append(periods, period)
+		}
+	}
+	sort.Slice(periods, func(i, j int) bool { return periods[i].Start.Before(periods[j].Start) })
+	return periods, nil
+}
+
+// Tasks returns the issues of the repository closed in the period
+func (s *GitHubSource) Tasks(projectID string, period Period) ([]models.Task, []string, error) {
+	issues, err := s.closedIssues(projectID, period.Start)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var tasks []models.Task
+	var warnings []string
+	for _, issue := range issues {
+		if !s.billable(issue) || !period.Contains(issue.ClosedAt) {
+			continue
+		}
+		task := models.Task{
+			Name:     issue.Title,
+			Done:     true,
+			Source:   NameGitHub,
+			SourceID: fmt.Sprintf("%s#%d", projectID, issue.Number),
+			Project:  projectID,
+			URL:      issue.HTMLURL,
+			Client:   s.config.Client,
+			Start:    issue.CreatedAt,
+			End:      issue.ClosedAt,
+		}
+		if issue.PullRequest != nil && issue.PullRequest.MergedAt != nil {
+			task.End = *issue.PullRequest.MergedAt
+		}
+		for _, label := range issue.Labels {
+			task.Labels = append(task.Labels, label.Name)
+		}
+
+		hours, found := estimateFromLabels(task.Labels)
+		if name, titleHours, titleFound, _ := process.ParseDuration(issue.Title); titleFound {
+			task.Name = name
+			if !found {
+				hours, found = titleHours, true
+			}
+		}
+		if !found {
+			warnings = append(warnings, fmt.Sprintf("%s has no estimate", task.SourceID))
+		}
+		task.TimeForTask = hours
+		tasks = append(tasks, task)
+	}
+
+	Categorize(tasks, s.config.Strategy)
+	return tasks, warnings, nil
+}
+
+/* ╭──────────────────────────────────────────╮ */
+/* │              AUX FUNCTIONS               │ */
+/* ╰──────────────────────────────────────────╯ */
+
+// closedIssues pages through the closed issues of a repository updated since since
+func (s *GitHubSource) closedIssues(repo string, since time.Time) ([]githubIssue, error) {
+	var issues []githubIssue
+	for page := 1; ; page++ {
+		query := url.Values{
+			"state":     {"closed"},
+			"per_page":  {strconv.Itoa(githubPageSize)},
+			"page":      {strconv.Itoa(page)},
+			"sort":      {"updated"},
+			"direction": {"asc"},
+		}
+		if !since.IsZero() {
+			// Closed after since means updated after since too
+			query.Set("since", since.UTC().Format(time.RFC3339))
+		}
+		request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/repos/%s/issues?%s", s.config.BaseURL, repo, query.Encode()), nil)
+		if err != nil {
+			return nil, err
+		}
+		request.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+		if s.config.Token != "" {
+			request.Header.Set("Authorization", "Bearer "+s.config.Token)
+		}
+
+		var pageIssues []githubIssue
+		if err := getJSON(s.config.HTTPClient, request, &pageIssues); err != nil {
+			return nil, fmt.Errorf("error fetching issues of %s: %w", repo, err)
+		}
+		issues = append(issues, pageIssues...)
+		if len(pageIssues) < githubPageSize {
+			return issues, nil
+		}
+	}
+}
+
+// billable tells whether an issue counts: issues closed as completed, and
+// merged pull requests when they are included
+func (s *GitHubSource) billable(issue githubIssue) bool {
+	if issue.ClosedAt.IsZero() {
+		return false
+	}
+	if issue.PullRequest == nil {
+		return issue.StateReason == "completed"
+	}
+	return s.config.PullRequests && issue.PullRequest.MergedAt != nil
+}
+
+// estimateFromLabels takes the hours of the first label holding a duration
+func estimateFromLabels(labels []string) (float64, bool) {
+	for _, label := range labels {
+		if _, hours, found, _ := process.ParseDuration(label); found {
+			return hours, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,181 @@
+package source
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const issuesToken = "test-token"
+
+// issueServer stands in for the GitHub and Jira REST APIs, serving the
+// fixtures of testdata and recording the JQL queries
+type issueServer struct {
+	*httptest.Server
+	mu      sync.Mutex
+	queries []string
+}
+
+func newIssueServer(t *testing.T) *issueServer {
+	t.Helper()
+	server := &issueServer{}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var fixture string
+		switch {
+		case r.URL.Path == "/repos/txeo/olympics/issues" && r.URL.Query().Get("state") == "closed":
+			if r.Header.Get("Authorization") != "Bearer "+issuesToken {
+				http.Error(w, `{"message": "Bad credentials"}`, http.StatusUnauthorized)
+				return
+			}
+			fixture = "github/issues.json"
+		case r.URL.Path == "/rest/api/3/project/OLY":
+			fixture = "jira/project.json"
+		case r.URL.Path == "/rest/api/3/search/jql":
+			if user, token, ok := r.BasicAuth(); !ok || user != "txeo@example.com" || token != issuesToken {
+				http.Error(w, `{"errorMessages": ["Unauthorized"]}`, http.StatusUnauthorized)
+				return
+			}
+			server.mu.Lock()
+			server.queries = append(server.queries, r.URL.Query().Get("jql"))
+			server.mu.Unlock()
+			fixture = "jira/search.json"
+			if token := r.URL.Query().Get("nextPageToken"); token != "" {
+				fixture = "jira/search_" + token + ".json"
+			}
+		default:
+			http.NotFound(w, r)
+			return
+		}
+
+		data, err := os.ReadFile(filepath.Join("testdata", fixture))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+type issueTask struct {
+	ID     string
+	Name   string
+	Hours  float64
+	Labels []string
+}
+
+func TestGitHubSourceTasks(t *testing.T) {
+	server := newIssueServer(t)
+	src := NewGitHubSource(GitHubConfig{BaseURL: server.URL, Token: issuesToken, Repos: []string{"txeo/olympics"}, Client: "IOC", PullRequests: true})
+
+	tasks, warnings, err := src.Tasks("txeo/olympics", MonthPeriod(2024, time.October))
+	if err != nil {
+		t.Fatalf("error fetching tasks: %v", err)
+	}
+
+	want := []issueTask{
+		{"txeo/olympics#12", "Fix login redirect", 2, []string{"bug", "estimate: 2h"}},
+		{"txeo/olympics#15", "Weekly call with IOC team", 1.5, []string{"meeting"}},
+		{"txeo/olympics#18", "Add checkout tests", 3, nil},
+		{"txeo/olympics#21", "Document the deploy", 0, []string{"documentation"}},
+	}
+	var got []issueTask
+	for _, task := range tasks {
+		got = append(got, issueTask{task.SourceID, task.Name, task.TimeForTask, task.Labels})
+		if task.Category == "" || task.Client != "IOC" || task.Source != NameGitHub || !task.Done {
+			t.Errorf("%s: got category %q, client %q, source %q, done %v", task.SourceID, task.Category, task.Client, task.Source, task.Done)
+		}
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got tasks\n%+v\nwant\n%+v", got, want)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "#21") {
+		t.Errorf("got warnings %q, want the missing estimate of #21", warnings)
+	}
+
+	periods, err := src.Periods("txeo/olympics")
+	if err != nil {
+		t.Fatalf("error fetching periods: %v", err)
+	}
+	if len(periods) != 2 || periods[0].Key() != "2024-10" || periods[1].Key() != "2024-11" {
+		t.Errorf("got periods %+v", periods)
+	}
+}
+
+func TestGitHubSourceErrors(t *testing.T) {
+	server := newIssueServer(t)
+
+	src := NewGitHubSource(GitHubConfig{BaseURL: server.URL, Token: "wrong", Repos: []string{"txeo/olympics"}})
+	if _, _, err := src.Tasks("txeo/olympics", MonthPeriod(2024, time.October)); !errors.Is(err, ErrRequestFailed) {
+		t.Errorf("got %v with a bad token, want ErrRequestFailed", err)
+	}
+	src = NewGitHubSource(GitHubConfig{BaseURL: server.URL, Token: issuesToken})
+	if _, _, err := src.Tasks("txeo/unknown", MonthPeriod(2024, time.October)); !errors.Is(err, ErrRequestFailed) {
+		t.Errorf("got %v for an unknown repository, want ErrRequestFailed", err)
+	}
+}
+
+func TestJiraSourceTasks(t *testing.T) {
+	server := newIssueServer(t)
+	src := NewJiraSource(JiraConfig{BaseURL: server.URL, Email: "txeo@example.com", Token: issuesToken, Projects: []string{"OLY"}})
+
+	projects, err := src.Projects()
+	if err != nil {
+		t.Fatalf("error fetching projects: %v", err)
+	}
+	if len(projects) != 1 || projects[0].ID != "OLY" || projects[0].Name != "Olympics" {
+		t.Errorf("got projects %+v", projects)
+	}
+
+	tasks, warnings, err := src.Tasks("OLY", MonthPeriod(2024, time.October))
+	if err != nil {
+		t.Fatalf("error fetching tasks: %v", err)
+	}
+
+	want := []issueTask{
+		{"OLY-101", "Fix login redirect", 1.5, []string{"bug", "Frontend"}},
+		{"OLY-102", "Weekly call with IOC team", 1, []string{"meeting"}},
+		{"OLY-103", "Document the deploy", 0, nil},
+	}
+	var got []issueTask
+	for _, task := range tasks {
+		got = append(got, issueTask{task.SourceID, task.Name, task.TimeForTask, task.Labels})
+		if task.Category == "" || task.Project != "OLY" || task.URL != server.URL+"/browse/"+task.SourceID {
+			t.Errorf("%s: got category %q, project %q, url %q", task.SourceID, task.Category, task.Project, task.URL)
+		}
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got tasks\n%+v\nwant\n%+v", got, want)
+	}
+	if len(warnings) != 2 {
+		t.Errorf("got warnings %q, want the estimate of OLY-102 and nothing on OLY-103", warnings)
+	}
+
+	if end := tasks[0].End.UTC(); !end.Equal(time.Date(2024, 10, 2, 16, 30, 0, 0, time.UTC)) {
+		t.Errorf("got resolution %v of OLY-101", end)
+	}
+	if len(server.queries) != 2 || !strings.Contains(server.queries[0], `project = "OLY" AND resolved >= "2024-09-30 00:00" AND resolved < "2024-11-02 00:00"`) {
+		t.Errorf("got queries %q", server.queries)
+	}
+}
+
+func TestJQLString(t *testing.T) {
+	for value, want := range map[string]string{
+		"OLY":            `"OLY"`,
+		`OLY" OR "a"="a`: `"OLY\" OR \"a\"=\"a"`,
+		`C:\projects\`:   `"C:\\projects\\"`,
+	} {
+		if got := jqlString(value); got != want {
+			t.Errorf("jqlString(%q) = %s, want %s", value, got, want)
+		}
+	}
+}
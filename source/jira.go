@@ -0,0 +1,245 @@
+package source
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"txeo-tools-library/models"
+	"txeo-tools-library/process"
+)
+
+// NameJira is the name of the Jira source
+const NameJira = "jira"
+
+const (
+	jiraPageSize   = 100
+	jiraTimeLayout = "2006-01-02T15:04:05.000-0700"
+	jiraFields     = "summary,labels,components,created,resolutiondate,timespent,timeoriginalestimate"
+)
+
+// JiraConfig tells JiraSource which projects to read and how
+type JiraConfig struct {
+	BaseURL    string                   // Like https://txeo.atlassian.net
+	Email      string                   // Account of the API token, the token is sent as bearer when empty
+	Token      string                   // API token
+	Projects   []string                 // Project keys
+	Client     string                   // Client the projects are billed to, if any
+	Strategy   process.CategoryStrategy // Categorizes the issues by labels, by summary keywords when nil
+	HTTPClient *http.Client             // http.DefaultClient when nil
+}
+
+// JiraSource reads the issues resolved in a period, with the time logged on
+// them or their original estimate when no work was logged. It uses the REST
+// API v3 enhanced search of Jira Cloud.
+type JiraSource struct {
+	config JiraConfig
+}
+
+// NewJiraSource reads the projects of config
+func NewJiraSource(config JiraConfig) *JiraSource {
+	config.BaseURL = strings.TrimRight(config.BaseURL, "/")
+	return &JiraSource{config: config}
+}
+
+// jiraIssue is the part of an issue of the REST API we use
+type jiraIssue struct {
+	ID     string `json:"id"`
+	Key    string `json:"key"`
+	Fields struct {
+		Summary    string   `json:"summary"`
+		Labels     []string `json:"labels"`
+		Components []struct {
+			Name string `json:"name"`
+		} `json:"components"`
+		Created              string `json:"created"`
+		ResolutionDate       string `json:"resolutiondate"`
+		TimeSpent            int    `json:"timespent"`            // Seconds
+		TimeOriginalEstimate int    `json:"timeoriginalestimate"` // Seconds
+	} `json:"fields"`
+}
+
+// jiraSearch is a page of the enhanced search, pages are chained by token
+type jiraSearch struct {
+	Issues        []jiraIssue `json:"issues"`
+	NextPageToken string      `json:"nextPageToken"`
+	IsLast        bool        `json:"isLast"`
+}
+
+func (s *JiraSource) Name() string {
+	return NameJira
+}
+
+// Projects returns the configured projects named as in Jira
+func (s *JiraSource) Projects() ([]Project, error) {
+	var projects []Project
+	for _, key := range s.config.Projects {
+		var project struct {
+			Key  string `json:"key"`
+			Name string `json:"name"`
+		}
+		request, err := s.newRequest("project/"+url.PathEscape(key), nil)
+		if err != nil {
+			return nil, err
+		}
+		if err := getJSON(s.config.HTTPClient, request, &project); err != nil {
+			return nil, fmt.Errorf("error fetching Jira project %s: %w", key, err)
+		}
+		projects = append(projects, Project{ID: project.Key, Name: project.Name, Source: NameJira})
+	}
+	return projects, nil
+}
+
+// Periods returns the months some issue of the project was resolved in
+func (s *JiraSource) Periods(projectID string) ([]Period, error) {
+	issues, err := s.search(fmt.Sprintf(`project = %s AND resolved IS NOT EMPTY ORDER BY resolved ASC`, jqlString(projectID)), "resolutiondate")
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	var periods []Period
+	for _, issue := range issues {
+		resolved, err := parseJiraTime(issue.Fields.ResolutionDate)
+		if err != nil {
+			continue
+		}
+		period := MonthPeriod(resolved.Year(), resolved.Month())
+		if !seen[period.Key()] {
+			seen[period.Key()] = true
+			periods = append(periods, period)
+		}
+	}
+	sort.Slice(periods, func(i, j int) bool { return periods[i].Start.Before(periods[j].Start) })
+	return periods, nil
+}
+
+// Tasks returns the issues of the project resolved in the period
+func (s *JiraSource) Tasks(projectID string, period Period) ([]models.Task, []string, error) {
+	// JQL dates are in the time zone of the user, so ask for a day more on
+	// each side and keep the issues resolved in the period
+	jql := fmt.Sprintf(`project = %s AND resolved >= "%s" AND resolved < "%s" ORDER BY resolved ASC`,
+		jqlString(projectID), period.Start.AddDate(0, 0, -1).Format("2006-01-02 15:04"), period.End.AddDate(0, 0, 1).Format("2006-01-02 15:04"))
+	issues, err := s.search(jql, jiraFields)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var tasks []models.Task
+	var warnings []string
+	for _, issue := range issues {
+		resolved, err := parseJiraTime(issue.Fields.ResolutionDate)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s: %v", issue.Key, err))
+			continue
+		}
+		if !period.Contains(resolved) {
+			continue
+		}
+		task := models.Task{
+			Name:     issue.Fields.Summary,
+			Done:     true,
+			Source:   NameJira,
+			SourceID: issue.Key,
+			Project:  projectID,
+			URL:      fmt.Sprintf("%s/browse/%s", s.config.BaseURL, issue.Key),
+			Labels:   append([]string(nil), issue.Fields.Labels...),
+			Client:   s.config.Client,
+		}
+		for _, component := range issue.Fields.Components {
+			task.Labels = append(task.Labels, component.Name)
+		}
+		if task.Start, err = parseJiraTime(issue.Fields.Created); err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s: %v", issue.Key, err))
+		}
+		task.End = resolved
+
+		switch {
+		case issue.Fields.TimeSpent > 0:
+			task.TimeForTask = secondsToHours(issue.Fields.TimeSpent)
+		case issue.Fields.TimeOriginalEstimate > 0:
+			task.TimeForTask = secondsToHours(issue.Fields.TimeOriginalEstimate)
+			warnings = append(warnings, fmt.Sprintf("%s has no logged work, using its estimate", issue.Key))
+		default:
+			warnings = append(warnings, fmt.Sprintf("%s has no logged work nor estimate", issue.Key))
+		}
+		tasks = append(tasks, task)
+	}
+
+	Categorize(tasks, s.config.Strategy)
+	return tasks, warnings, nil
+}
+
+/* ╭──────────────────────────────────────────╮ */
+/* │              AUX FUNCTIONS               │ */
+/* ╰──────────────────────────────────────────╯ */
+func (s *JiraSource) newRequest(path string, query url.Values) (*http.Request, error) {
+	address := fmt.Sprintf("%s/rest/api/3/%s", s.config.BaseURL, path)
+	if len(query) > 0 {
+		address += "?" + query.Encode()
+	}
+	request, err := http.NewRequest(http.MethodGet, address, nil)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case s.config.Email != "":
+		request.SetBasicAuth(s.config.Email, s.config.Token)
+	case s.config.Token != "":
+		request.Header.Set("Authorization", "Bearer "+s.config.Token)
+	}
+	return request, nil
+}
+
+// search pages through the issues matching jql
+func (s *JiraSource) search(jql, fields string) ([]jiraIssue, error) {
+	var issues []jiraIssue
+	token := ""
+	for {
+		query := url.Values{
+			"jql":        {jql},
+			"fields":     {fields},
+			"maxResults": {strconv.Itoa(jiraPageSize)},
+		}
+		if token != "" {
+			query.Set("nextPageToken", token)
+		}
+		request, err := s.newRequest("search/jql", query)
+		if err != nil {
+			return nil, err
+		}
+		var page jiraSearch
+		if err := getJSON(s.config.HTTPClient, request, &page); err != nil {
+			return nil, fmt.Errorf("error searching Jira issues: %w", err)
+		}
+		issues = append(issues, page.Issues...)
+		if page.IsLast || page.NextPageToken == "" {
+			return issues, nil
+		}
+		token = page.NextPageToken
+	}
+}
+
+// jqlString quotes value as a JQL string
+func jqlString(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+func parseJiraTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(jiraTimeLayout, value)
+	if err != nil {
+		return time.Parse(time.RFC3339, value)
+	}
+	return t, nil
+}
+
+func secondsToHours(seconds int) float64 {
+	return math.Round(float64(seconds)/36) / 100
+}
@@ -0,0 +1,145 @@
+package source
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"txeo-tools-library/models"
+	"txeo-tools-library/process"
+
+	"github.com/adlio/trello"
+)
+
+var (
+	ErrUnknownProject = errors.New("unknown project")
+	ErrInvalidPeriod  = errors.New("invalid period")
+	ErrRequestFailed  = errors.New("request failed")
+)
+
+var periodRegex = regexp.MustCompile(`^(\d{4})-(\d{1,2})$`) // 2024-10
+
+// TaskSource is somewhere work is tracked: Trello boards, issue trackers or timesheets
+type TaskSource interface {
+	Name() string                                                           // Short name of the source, like "trello"
+	Projects() ([]Project, error)                                           // Boards, repositories or projects of the source
+	Periods(projectID string) ([]Period, error)                             // Periods with tasks of a project, oldest first
+	Tasks(projectID string, period Period) ([]models.Task, []string, error) // Tasks of a project in a period, with warnings
+}
+
+// Project is a board, repository or project of a source
+type Project struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Source string `json:"source"`
+}
+
+// Period is a span of time tasks are reported by, usually a month
+type Period struct {
+	Name  string    `json:"name"`  // As the source names it, like "Octubre 2024"
+	Start time.Time `json:"start"` // Inclusive
+	End   time.Time `json:"end"`   // Exclusive
+}
+
+// MonthPeriod is the period of a month
+func MonthPeriod(year int, month time.Month) Period {
+	start := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	return Period{Name: fmt.Sprintf("%s %d", month, year), Start: start, End: start.AddDate(0, 1, 0)}
+}
+
+// ParsePeriod understands months written as 2024-10
+func ParsePeriod(value string) (Period, error) {
+	groups := periodRegex.FindStringSubmatch(value)
+	if groups == nil {
+		return Period{}, fmt.Errorf("%w: %q, expected YYYY-MM", ErrInvalidPeriod, value)
+	}
+	year, _ := strconv.Atoi(groups[1])
+	month, _ := strconv.Atoi(groups[2])
+	if month < 1 || month > 12 {
+		return Period{}, fmt.Errorf("%w: %q, expected YYYY-MM", ErrInvalidPeriod, value)
+	}
+	return MonthPeriod(year, time.Month(month)), nil
+}
+
+// IsMonth tells whether the period is exactly a calendar month
+func (p Period) IsMonth() bool {
+	return p.Start.Day() == 1 && p.Start.Hour() == 0 && p.End.Equal(p.Start.AddDate(0, 1, 0))
+}
+
+// Contains tells whether t falls in the period
+func (p Period) Contains(t time.Time) bool {
+	return !t.Before(p.Start) && t.Before(p.End)
+}
+
+// Key identifies the period, like 2024-10 for a month
+func (p Period) Key() string {
+	if p.IsMonth() {
+		return p.Start.Format("2006-01")
+	}
+	return p.Start.Format("2006-01-02") + ".." + p.End.Format("2006-01-02")
+}
+
+// FindProject looks a project up by ID or name
+func FindProject(src TaskSource, project string) (Project, error) {
+	projects, err := src.Projects()
+	if err != nil {
+		return Project{}, err
+	}
+	for _, p := range projects {
+		if p.ID == project || p.Name == project {
+			return p, nil
+		}
+	}
+	return Project{}, fmt.Errorf("%w: %s in %s", ErrUnknownProject, project, src.Name())
+}
+
+// Categorize sets the category of the tasks without one, using their labels
+// with strategy, or the name keywords when strategy is nil
+func Categorize(tasks []models.Task, strategy process.CategoryStrategy) {
+	for i, task := range tasks {
+		if task.Category != "" {
+			continue
+		}
+		if strategy == nil {
+			tasks[i].Category = process.GetTaskCategory(task.Name)
+			continue
+		}
+		input := process.CardCategoryInput{Name: task.Name}
+		for _, label := range task.Labels {
+			input.Labels = append(input.Labels, &trello.Label{Name: label})
+		}
+		tasks[i].Category = process.Categorize(strategy, input)
+	}
+}
+
+/* ╭──────────────────────────────────────────╮ */
+/* │              AUX FUNCTIONS               │ */
+/* ╰──────────────────────────────────────────╯ */
+
+// getJSON sends request and decodes the JSON response into target
+func getJSON(client *http.Client, request *http.Request, target interface{}) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	request.Header.Set("Accept", "application/json")
+	response, err := client.Do(request)
+	if err != nil {
+		return fmt.Errorf("error requesting %s: %w", request.URL.Path, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(response.Body, 512))
+		return fmt.Errorf("%w: %s %s: %s %s", ErrRequestFailed, request.Method, request.URL.Path, response.Status, strings.TrimSpace(string(body)))
+	}
+	if err := json.NewDecoder(response.Body).Decode(target); err != nil {
+		return fmt.Errorf("error decoding %s: %w", request.URL.Path, err)
+	}
+	return nil
+}
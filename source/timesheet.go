@@ -0,0 +1,522 @@
+package source
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"txeo-tools-library/models"
+	"txeo-tools-library/process"
+)
+
+// Timesheet export formats
+const (
+	FormatToggl    = "toggl"
+	FormatClockify = "clockify"
+	FormatHarvest  = "harvest"
+)
+
+var (
+	ErrUnknownFormat = errors.New("unknown timesheet format")
+	ErrMissingColumn = errors.New("missing timesheet column")
+)
+
+// ColumnMapping names the CSV columns holding each value of a time entry.
+// Names are matched ignoring case, empty names are not read.
+type ColumnMapping struct {
+	Project     string   `json:"project,omitempty"`
+	Client      string   `json:"client,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Task        string   `json:"task,omitempty"` // Task or activity type, kept as a label
+	Tags        string   `json:"tags,omitempty"` // Comma separated
+	StartDate   string   `json:"startDate,omitempty"`
+	StartTime   string   `json:"startTime,omitempty"`
+	EndDate     string   `json:"endDate,omitempty"`
+	EndTime     string   `json:"endTime,omitempty"`
+	Duration    string   `json:"duration,omitempty"`    // 01:30:00, 1:30, 1.5 or 1h30
+	DateLayouts []string `json:"dateLayouts,omitempty"` // Go layouts tried in order, see slashDateLayout
+	TimeLayouts []string `json:"timeLayouts,omitempty"`
+}
+
+// Columns of the standard detailed exports
+var (
+	TogglColumns = ColumnMapping{
+		Project: "Project", Client: "Client", Description: "Description", Task: "Task", Tags: "Tags",
+		StartDate: "Start date", StartTime: "Start time", EndDate: "End date", EndTime: "End time", Duration: "Duration",
+		DateLayouts: []string{"2006-01-02"},
+	}
+	ClockifyColumns = ColumnMapping{
+		Project: "Project", Client: "Client", Description: "Description", Task: "Task", Tags: "Tags",
+		StartDate: "Start Date", StartTime: "Start Time", EndDate: "End Date", EndTime: "End Time", Duration: "Duration (decimal)",
+		DateLayouts: []string{"2006-01-02"},
+	}
+	HarvestColumns = ColumnMapping{
+		Project: "Project", Client: "Client", Description: "Notes", Task: "Task",
+		StartDate: "Date", Duration: "Hours",
+		DateLayouts: []string{"2006-01-02"},
+	}
+)
+
+var defaultTimeLayouts = []string{"15:04:05", "15:04", "03:04:05 PM", "3:04:05 PM", "03:04 PM", "3:04 PM"}
+
+// slashDateRegex matches dates like 10/21/2024, month or day first
+var slashDateRegex = regexp.MustCompile(`^(\d{2})/(\d{2})/\d{4}$`)
+
+// TimesheetOptions tunes ImportTimesheet
+type TimesheetOptions struct {
+	Format   string                   // toggl, clockify or harvest, detected from the header when empty
+	Columns  ColumnMapping            // Overrides the columns of the format, field by field
+	Location *time.Location           // Time zone of the dates and times, UTC when nil
+	Comma    rune                     // Field separator, ',' when zero
+	Strategy process.CategoryStrategy // Categorizes the entries by their tags, by name keywords when nil
+}
+
+// ImportTimesheet reads the time entries of a CSV export as tasks. Entries
+// that can't be read are skipped with a warning. Dates like 10/21/2024 are
+// read month or day first as the date layouts say, or as the entries show
+// when some day is over 12.
+func ImportTimesheet(r io.Reader, options TimesheetOptions) ([]models.Task, []string, error) {
+	tasks, lineWarnings, err := importTimesheet(r, options)
+	var warnings []string
+	for _, warning := range lineWarnings {
+		warnings = append(warnings, warning.message)
+	}
+	return tasks, warnings, err
+}
+
+// ImportTimesheetFile reads the time entries of a CSV export file
+func ImportTimesheetFile(path string, options TimesheetOptions) ([]models.Task, []string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+	return ImportTimesheet(file, options)
+}
+
+// DetectTimesheetFormat guesses the format of an export from its header
+func DetectTimesheetFormat(header []string) (string, bool) {
+	has := func(name string) bool {
+		for _, column := range header {
+			if strings.EqualFold(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff")), name) {
+				return true
+			}
+		}
+		return false
+	}
+	switch {
+	case has("Notes") && has("Hours"):
+		return FormatHarvest, true
+	case has("Duration (decimal)") || has("Duration (h)"):
+		return FormatClockify, true
+	case has("Start date") && has("Duration"):
+		return FormatToggl, true
+	}
+	return "", false
+}
+
+// LoadColumnMapping reads a column mapping override from a JSON file
+func LoadColumnMapping(path string) (ColumnMapping, error) {
+	var columns ColumnMapping
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return columns, fmt.Errorf("error reading column mapping: %w", err)
+	}
+	if err := json.Unmarshal(data, &columns); err != nil {
+		return columns, fmt.Errorf("error decoding column mapping %s: %w", path, err)
+	}
+	return columns, nil
+}
+
+// TimesheetSource serves the entries of a timesheet export as a TaskSource,
+// its projects being the projects of the entries and its periods months
+type TimesheetSource struct {
+	name     string
+	tasks    []models.Task
+	warnings []timesheetWarning
+}
+
+// timesheetWarning is a skipped entry, with what could be read of it
+type timesheetWarning struct {
+	project string
+	start   time.Time // Zero when the date couldn't be read
+	message string
+}
+
+// NewTimesheetSource imports a timesheet export file
+func NewTimesheetSource(path string, options TimesheetOptions) (*TimesheetSource, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	tasks, warnings, err := importTimesheet(file, options)
+	if err != nil {
+		return nil, err
+	}
+	name := options.Format
+	if len(tasks) > 0 {
+		name = tasks[0].Source
+	}
+	return &TimesheetSource{name: name, tasks: tasks, warnings: warnings}, nil
+}
+
+func (s *TimesheetSource) Name() string {
+	return s.name
+}
+
+func (s *TimesheetSource) Projects() ([]Project, error) {
+	seen := map[string]bool{}
+	var projects []Project
+	for _, task := range s.tasks {
+		if seen[task.Project] {
+			continue
+		}
+		seen[task.Project] = true
+		projects = append(projects, Project{ID: task.Project, Name: task.Project, Source: s.name})
+	}
+	sort.Slice(projects, func(i, j int) bool { return projects[i].Name < projects[j].Name })
+	return projects, nil
+}
+
+func (s *TimesheetSource) Periods(projectID string) ([]Period, error) {
+	seen := map[string]bool{}
+	var periods []Period
+	for _, task := range s.tasks {
+		if task.Project != projectID {
+			continue
+		}
+		period := MonthPeriod(task.Start.Year(), task.Start.Month())
+		if !seen[period.Key()] {
+			seen[period.Key()] = true
+			periods = append(periods, period)
+		}
+	}
+	sort.Slice(periods, func(i, j int) bool { return periods[i].Start.Before(periods[j].Start) })
+	return periods, nil
+}
+
+// Tasks returns the entries of a project started in the period, with the
+// warnings of the entries of the project skipped in the period, or anytime
+// when their date couldn't be read
+func (s *TimesheetSource) Tasks(projectID string, period Period) ([]models.Task, []string, error) {
+	var tasks []models.Task
+	for _, task := range s.tasks {
+		if task.Project == projectID && period.Contains(task.Start) {
+			tasks = append(tasks, task)
+		}
+	}
+	var warnings []string
+	for _, warning := range s.warnings {
+		if warning.project == projectID && (warning.start.IsZero() || period.Contains(warning.start)) {
+			warnings = append(warnings, warning.message)
+		}
+	}
+	return tasks, warnings, nil
+}
+
+/* ╭──────────────────────────────────────────╮ */
+/* │              AUX FUNCTIONS               │ */
+/* ╰──────────────────────────────────────────╯ */
+
+// importTimesheet reads the entries of a CSV export, with a warning for each skipped entry
+func importTimesheet(r io.Reader, options TimesheetOptions) ([]models.Task, []timesheetWarning, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	if options.Comma != 0 {
+		reader.Comma = options.Comma
+	}
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading timesheet header: %w", err)
+	}
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+
+	format := options.Format
+	if format == "" {
+		format, _ = DetectTimesheetFormat(header)
+	}
+	columns, err := timesheetColumns(format, options.Columns)
+	if err != nil {
+		return nil, nil, err
+	}
+	index, err := columnIndex(header, columns)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Every record first, the order of the dates may show up in any of them
+	var records [][]string
+	var readErr error
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			readErr = fmt.Errorf("error reading timesheet line %d: %w", len(records)+2, err)
+			break
+		}
+		records = append(records, record)
+	}
+	if len(options.Columns.DateLayouts) == 0 {
+		if layout, ok := slashDateLayout(records, index); ok {
+			columns.DateLayouts = append(columns.DateLayouts, layout)
+		}
+	}
+
+	location := options.Location
+	if location == nil {
+		location = time.UTC
+	}
+	name := format
+	if name == "" {
+		name = "timesheet"
+	}
+
+	var tasks []models.Task
+	var warnings []timesheetWarning
+	for i, record := range records {
+		line := i + 2
+		task, err := timesheetTask(record, index, columns, location)
+		if err != nil {
+			warnings = append(warnings, timesheetWarning{project: task.Project, start: task.Start, message: fmt.Sprintf("line %d skipped: %v", line, err)})
+			continue
+		}
+		task.Source = name
+		task.SourceID = strconv.Itoa(line)
+		tasks = append(tasks, task)
+	}
+
+	Categorize(tasks, options.Strategy)
+	return tasks, warnings, readErr
+}
+
+// slashDateLayout tells whether the dates like 10/21/2024 of the entries are
+// month or day first, as shown by some day over 12. It's not found when no
+// date shows it, or when some show each order.
+func slashDateLayout(records [][]string, index map[string]int) (string, bool) {
+	var monthFirst, dayFirst bool
+	for _, record := range records {
+		for _, column := range []string{"startDate", "endDate"} {
+			i := index[column]
+			if i < 0 || i >= len(record) {
+				continue
+			}
+			groups := slashDateRegex.FindStringSubmatch(strings.TrimSpace(record[i]))
+			if groups == nil {
+				continue
+			}
+			first, _ := strconv.Atoi(groups[1])
+			second, _ := strconv.Atoi(groups[2])
+			monthFirst = monthFirst || second > 12
+			dayFirst = dayFirst || first > 12
+		}
+	}
+	switch {
+	case monthFirst && !dayFirst:
+		return "01/02/2006", true
+	case dayFirst && !monthFirst:
+		return "02/01/2006", true
+	}
+	return "", false
+}
+
+// timesheetColumns returns the columns of format with the non empty overrides applied
+func timesheetColumns(format string, override ColumnMapping) (ColumnMapping, error) {
+	var columns ColumnMapping
+	switch format {
+	case FormatToggl:
+		columns = TogglColumns
+	case FormatClockify:
+		columns = ClockifyColumns
+	case FormatHarvest:
+		columns = HarvestColumns
+	case "":
+		if override.StartDate == "" {
+			return columns, fmt.Errorf("%w: set the format or the columns", ErrUnknownFormat)
+		}
+	default:
+		return columns, fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+
+	fields := []struct{ target, value *string }{
+		{&columns.Project, &override.Project}, {&columns.Client, &override.Client},
+		{&columns.Description, &override.Description}, {&columns.Task, &override.Task}, {&columns.Tags, &override.Tags},
+		{&columns.StartDate, &override.StartDate}, {&columns.StartTime, &override.StartTime},
+		{&columns.EndDate, &override.EndDate}, {&columns.EndTime, &override.EndTime}, {&columns.Duration, &override.Duration},
+	}
+	for _, field := range fields {
+		if *field.value != "" {
+			*field.target = *field.value
+		}
+	}
+	if len(override.DateLayouts) > 0 {
+		columns.DateLayouts = override.DateLayouts
+	}
+	if len(override.TimeLayouts) > 0 {
+		columns.TimeLayouts = override.TimeLayouts
+	}
+	if len(columns.DateLayouts) == 0 {
+		columns.DateLayouts = []string{"2006-01-02"}
+	}
+	if len(columns.TimeLayouts) == 0 {
+		columns.TimeLayouts = defaultTimeLayouts
+	}
+	return columns, nil
+}
+
+// columnIndex finds the position of every mapped column, -1 when not mapped
+// or missing. The start date and either a description or a task are required.
+func columnIndex(header []string, columns ColumnMapping) (map[string]int, error) {
+	find := func(name string) int {
+		if name == "" {
+			return -1
+		}
+		for i, column := range header {
+			if strings.EqualFold(strings.TrimSpace(column), name) {
+				return i
+			}
+		}
+		return -1
+	}
+	index := map[string]int{
+		"project": find(columns.Project), "client": find(columns.Client),
+		"description": find(columns.Description), "task": find(columns.Task), "tags": find(columns.Tags),
+		"startDate": find(columns.StartDate), "startTime": find(columns.StartTime),
+		"endDate": find(columns.EndDate), "endTime": find(columns.EndTime), "duration": find(columns.Duration),
+	}
+	if index["startDate"] < 0 {
+		return nil, fmt.Errorf("%w: %q", ErrMissingColumn, columns.StartDate)
+	}
+	if index["description"] < 0 && index["task"] < 0 {
+		return nil, fmt.Errorf("%w: %q", ErrMissingColumn, columns.Description)
+	}
+	if index["duration"] < 0 && index["endTime"] < 0 {
+		return nil, fmt.Errorf("%w: %q", ErrMissingColumn, columns.Duration)
+	}
+	return index, nil
+}
+
+func timesheetTask(record []string, index map[string]int, columns ColumnMapping, location *time.Location) (models.Task, error) {
+	value := func(column string) string {
+		if i := index[column]; i >= 0 && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	task := models.Task{
+		Name:    value("description"),
+		Project: value("project"),
+		Client:  value("client"),
+	}
+	if activity := value("task"); activity != "" {
+		if task.Name == "" {
+			task.Name = activity
+		}
+		task.Labels = append(task.Labels, activity)
+	}
+	for _, tag := range strings.Split(value("tags"), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			task.Labels = append(task.Labels, tag)
+		}
+	}
+
+	start, err := parseDateTime(value("startDate"), value("startTime"), columns, location)
+	if err != nil {
+		return task, err
+	}
+	task.Start = start
+
+	if value("endTime") != "" {
+		endDate := value("endDate")
+		if endDate == "" {
+			endDate = value("startDate")
+		}
+		end, err := parseDateTime(endDate, value("endTime"), columns, location)
+		if err != nil {
+			return task, err
+		}
+		if end.Before(start) {
+			end = end.AddDate(0, 0, 1) // Past midnight without end date
+		}
+		task.End = end
+	}
+
+	if duration := value("duration"); duration != "" {
+		hours, err := parseHours(duration)
+		if err != nil {
+			return task, err
+		}
+		task.TimeForTask = hours
+	} else if !task.End.IsZero() {
+		task.TimeForTask = math.Round(task.End.Sub(task.Start).Hours()*100) / 100
+	}
+	if task.End.IsZero() && value("startTime") != "" {
+		task.End = task.Start.Add(time.Duration(task.TimeForTask * float64(time.Hour)))
+	}
+	return task, nil
+}
+
+func parseDateTime(date, clock string, columns ColumnMapping, location *time.Location) (time.Time, error) {
+	if date == "" {
+		return time.Time{}, errors.New("no date")
+	}
+	var day time.Time
+	var err error
+	for _, layout := range columns.DateLayouts {
+		if day, err = time.ParseInLocation(layout, date, location); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		if slashDateRegex.MatchString(date) {
+			return time.Time{}, fmt.Errorf("ambiguous date %q, set the date layout to 01/02/2006 or 02/01/2006", date)
+		}
+		return time.Time{}, fmt.Errorf("invalid date %q", date)
+	}
+	if clock == "" {
+		return day, nil
+	}
+	for _, layout := range columns.TimeLayouts {
+		if t, err := time.Parse(layout, clock); err == nil {
+			return time.Date(day.Year(), day.Month(), day.Day(), t.Hour(), t.Minute(), t.Second(), 0, location), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q", clock)
+}
+
+// parseHours reads durations as exported by the timesheets: 01:30:00, 1:30,
+// 1.5, 1,5 or written like the task names, 1h30
+func parseHours(value string) (float64, error) {
+	if parts := strings.Split(value, ":"); len(parts) == 2 || len(parts) == 3 {
+		var total float64
+		for i, part := range parts {
+			n, err := strconv.Atoi(part)
+			if err != nil || n < 0 {
+				return 0, fmt.Errorf("invalid duration %q", value)
+			}
+			total += float64(n) / math.Pow(60, float64(i))
+		}
+		return math.Round(total*100) / 100, nil
+	}
+	if hours, err := strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64); err == nil {
+		return math.Round(hours*100) / 100, nil
+	}
+	if _, hours, found, _ := process.ParseDuration(value); found {
+		return hours, nil
+	}
+	return 0, fmt.Errorf("invalid duration %q", value)
+}
@@ -0,0 +1,189 @@
+package source
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type timesheetEntry struct {
+	Name    string
+	Project string
+	Client  string
+	Start   string
+	End     string
+	Hours   float64
+	Labels  []string
+}
+
+func TestImportTimesheet(t *testing.T) {
+	tests := []struct {
+		file     string
+		options  TimesheetOptions
+		source   string
+		want     []timesheetEntry
+		warnings int
+	}{
+		{
+			file:   "toggl.csv",
+			source: FormatToggl,
+			want: []timesheetEntry{
+				{"Weekly call with IOC team", "Olympics", "IOC", "2024-10-01 09:00", "2024-10-01 10:30", 1.5, []string{"meeting"}},
+				{"Fix login redirect", "Olympics", "IOC", "2024-10-02 15:00", "2024-10-02 17:00", 2, []string{"bug", "frontend"}},
+				{"Deploy release", "LivGolf", "LIV", "2024-10-31 23:30", "2024-11-01 00:15", 0.75, nil},
+			},
+			warnings: 2,
+		},
+		{
+			file:   "clockify.csv",
+			source: FormatClockify,
+			want: []timesheetEntry{
+				{"Documentation for the handover", "Olympics", "IOC", "2024-10-21 11:00", "2024-10-21 12:30", 1.5, nil},
+				{"Code review", "Olympics", "IOC", "2024-10-22 16:00", "2024-10-22 16:45", 0.75, []string{"development"}},
+			},
+		},
+		{
+			file:   "harvest.csv",
+			source: FormatHarvest,
+			want: []timesheetEntry{
+				{"Sprint planning", "Olympics", "IOC", "2024-10-03 00:00", "", 1.25, []string{"Meetings"}},
+				{"Development", "Olympics", "IOC", "2024-10-04 00:00", "", 2.5, []string{"Development"}},
+			},
+		},
+		{
+			file: "custom.csv",
+			options: TimesheetOptions{
+				Comma:   ';',
+				Columns: ColumnMapping{Client: "Cliente", Project: "Proyecto", Description: "Tarea", StartDate: "Día", Duration: "Tiempo", DateLayouts: []string{"02/01/2006"}},
+			},
+			source: "timesheet",
+			want: []timesheetEntry{
+				{"Reunión semanal", "Olympics", "IOC", "2024-10-03 00:00", "", 1.5, nil},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.file, func(t *testing.T) {
+			tasks, warnings, err := ImportTimesheetFile(filepath.Join("testdata", "timesheets", test.file), test.options)
+			if err != nil {
+				t.Fatalf("error importing: %v", err)
+			}
+			if len(warnings) != test.warnings {
+				t.Errorf("got warnings %q, want %d", warnings, test.warnings)
+			}
+
+			var got []timesheetEntry
+			for _, task := range tasks {
+				if task.Source != test.source {
+					t.Errorf("%s: source %q, want %q", task.Name, task.Source, test.source)
+				}
+				if task.Category == "" {
+					t.Errorf("%s: not categorized", task.Name)
+				}
+				got = append(got, timesheetEntry{task.Name, task.Project, task.Client, formatEntryTime(task.Start), formatEntryTime(task.End), task.TimeForTask, task.Labels})
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got entries\n%+v\nwant\n%+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestImportTimesheetSlashDates(t *testing.T) {
+	const header = "Project,Description,Start Date,Start Time,Duration (decimal)\n"
+	tests := []struct {
+		name    string
+		rows    string
+		layouts []string
+		want    []string // Start dates, empty when the entry is skipped
+	}{
+		{"month first", "Olympics,Call,10/03/2024,09:00,1\nOlympics,Review,10/21/2024,09:00,1\n", nil, []string{"2024-10-03", "2024-10-21"}},
+		{"day first", "Olympics,Call,03/10/2024,09:00,1\nOlympics,Review,21/10/2024,09:00,1\n", nil, []string{"2024-10-03", "2024-10-21"}},
+		{"ambiguous", "Olympics,Call,10/03/2024,09:00,1\nOlympics,Review,2024-10-21,09:00,1\n", nil, []string{"", "2024-10-21"}},
+		{"both orders", "Olympics,Call,10/21/2024,09:00,1\nOlympics,Review,21/10/2024,09:00,1\n", nil, []string{"", ""}},
+		{"chosen layout", "Olympics,Call,10/03/2024,09:00,1\n", []string{"02/01/2006"}, []string{"2024-03-10"}},
+	}
+	for _, test := range tests {
+		options := TimesheetOptions{Format: FormatClockify, Columns: ColumnMapping{DateLayouts: test.layouts}}
+		tasks, warnings, err := ImportTimesheet(strings.NewReader(header+test.rows), options)
+		if err != nil {
+			t.Fatalf("%s: error importing: %v", test.name, err)
+		}
+		var got []string
+		for _, task := range tasks {
+			got = append(got, task.Start.Format("2006-01-02"))
+		}
+		var want []string
+		skipped := 0
+		for _, date := range test.want {
+			if date == "" {
+				skipped++
+			} else {
+				want = append(want, date)
+			}
+		}
+		if !reflect.DeepEqual(got, want) || len(warnings) != skipped {
+			t.Errorf("%s: got dates %q and warnings %q, want %q", test.name, got, warnings, test.want)
+		}
+		for _, warning := range warnings {
+			if !strings.Contains(warning, "ambiguous date") {
+				t.Errorf("%s: got warning %q, want an ambiguous date", test.name, warning)
+			}
+		}
+	}
+}
+
+func TestImportTimesheetUnknownFormat(t *testing.T) {
+	_, _, err := ImportTimesheet(strings.NewReader("When,What\n2024-10-01,Call\n"), TimesheetOptions{})
+	if !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("got %v, want ErrUnknownFormat", err)
+	}
+}
+
+func TestTimesheetSource(t *testing.T) {
+	src, err := NewTimesheetSource(filepath.Join("testdata", "timesheets", "toggl.csv"), TimesheetOptions{})
+	if err != nil {
+		t.Fatalf("error importing: %v", err)
+	}
+
+	projects, _ := src.Projects()
+	if len(projects) != 2 || projects[0].Name != "LivGolf" || projects[1].Name != "Olympics" {
+		t.Errorf("got projects %+v", projects)
+	}
+	periods, _ := src.Periods("Olympics")
+	if len(periods) != 1 || periods[0].Key() != "2024-10" {
+		t.Errorf("got periods %+v", periods)
+	}
+	tasks, _, _ := src.Tasks("Olympics", MonthPeriod(2024, time.October))
+	if len(tasks) != 2 {
+		t.Errorf("got %d tasks of Olympics in October, want 2", len(tasks))
+	}
+
+	// The broken row of Olympics has no date, the LivGolf one is of November
+	for _, test := range []struct {
+		project string
+		period  Period
+		line    string
+	}{
+		{"Olympics", MonthPeriod(2024, time.October), "line 5"},
+		{"Olympics", MonthPeriod(2024, time.November), "line 5"},
+		{"LivGolf", MonthPeriod(2024, time.October), ""},
+		{"LivGolf", MonthPeriod(2024, time.November), "line 6"},
+	} {
+		_, warnings, _ := src.Tasks(test.project, test.period)
+		if test.line == "" && len(warnings) != 0 || test.line != "" && (len(warnings) != 1 || !strings.HasPrefix(warnings[0], test.line)) {
+			t.Errorf("%s in %s: got warnings %q, want %q", test.project, test.period.Key(), warnings, test.line)
+		}
+	}
+}
+
+func formatEntryTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02 15:04")
+}
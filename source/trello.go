@@ -0,0 +1,135 @@
+package source
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"txeo-tools-library/models"
+	"txeo-tools-library/process"
+	ttrello "txeo-tools-library/trello"
+
+	"github.com/adlio/trello"
+)
+
+// NameTrello is the name of the Trello source
+const NameTrello = "trello"
+
+// TrelloOptions tunes how the cards of the boards are turned into tasks
+type TrelloOptions struct {
+	HoursField string                   // Name of the custom field holding the hours of a card, if any
+	Strategy   process.CategoryStrategy // Categorizes the cards by labels or custom fields, by name keywords when nil
+}
+
+// TrelloSource reads the tasks of Trello boards, a period being a month list
+type TrelloSource struct {
+	src     ttrello.Source
+	boards  []ttrello.BoardInfo
+	options TrelloOptions
+}
+
+// NewTrelloSource reads the given boards from src, which may be the live API, the cache or an archive
+func NewTrelloSource(src ttrello.Source, boards []ttrello.BoardInfo, options TrelloOptions) *TrelloSource {
+	return &TrelloSource{src: src, boards: boards, options: options}
+}
+
+func (s *TrelloSource) Name() string {
+	return NameTrello
+}
+
+func (s *TrelloSource) Projects() ([]Project, error) {
+	projects := make([]Project, 0, len(s.boards))
+	for _, board := range s.boards {
+		projects = append(projects, Project{ID: board.ID, Name: board.Name, Source: NameTrello})
+	}
+	return projects, nil
+}
+
+// Periods returns the months of the month lists of a board. Lists named
+// without a year are left out, their month can't be placed in time.
+func (s *TrelloSource) Periods(projectID string) ([]Period, error) {
+	lists, err := s.src.GetLists(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching lists of board %s: %w", projectID, err)
+	}
+
+	seen := map[string]bool{}
+	var periods []Period
+	for _, list := range lists {
+		listPeriod, ok := ttrello.ParseListPeriod(list.Name)
+		if !ok || listPeriod.Year == 0 {
+			continue
+		}
+		period := MonthPeriod(listPeriod.Year, listPeriod.Month)
+		if seen[period.Key()] {
+			continue
+		}
+		seen[period.Key()] = true
+		period.Name = list.Name
+		periods = append(periods, period)
+	}
+	sort.Slice(periods, func(i, j int) bool { return periods[i].Start.Before(periods[j].Start) })
+	return periods, nil
+}
+
+// Tasks returns the cards of the month list of the period, which must be a month
+func (s *TrelloSource) Tasks(projectID string, period Period) ([]models.Task, []string, error) {
+	if !period.IsMonth() {
+		return nil, nil, fmt.Errorf("%w: %s, Trello tasks are kept by month", ErrInvalidPeriod, period.Key())
+	}
+	month := models.Months{}.GetMonths()[period.Start.Month()-1]
+	year := models.Year{Name: strconv.Itoa(period.Start.Year()), Time: time.Date(period.Start.Year(), 1, 1, 0, 0, 0, 0, time.UTC)}
+	return s.MonthTasks(projectID, month, year)
+}
+
+// MonthTasks returns the cards of the month lists of a board, month may be
+// "All Year" and year "All Years"
+func (s *TrelloSource) MonthTasks(projectID string, month models.Month, year models.Year) ([]models.Task, []string, error) {
+	board, err := FindProject(s, projectID)
+	if err != nil {
+		return nil, nil, err
+	}
+	monthLists, err := ttrello.FetchMonthLists(s.src, board.ID, month, year)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var customFields []*trello.CustomField
+	if s.options.HoursField != "" || s.options.Strategy != nil {
+		customFields, err = s.src.GetCustomFields(board.ID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error fetching custom fields of board %s: %w", board.Name, err)
+		}
+	}
+	boardLabels, err := s.src.GetLabels(board.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error fetching labels of board %s: %w", board.Name, err)
+	}
+	labels := map[string]*trello.Label{}
+	for _, label := range boardLabels {
+		labels[label.ID] = label
+	}
+
+	var tasks []models.Task
+	var warnings []string
+	for _, monthList := range monthLists {
+		for _, card := range monthList.Cards {
+			task, taskWarnings := process.GetTaskFromCard(card, customFields, s.options.HoursField)
+			input := ttrello.CategoryInput(task.Name, card, labels, customFields)
+			if s.options.Strategy != nil {
+				task.Category = process.Categorize(s.options.Strategy, input)
+			}
+			task.Source = NameTrello
+			task.SourceID = card.ID
+			task.Project = board.Name
+			task.URL = card.ShortURL
+			for _, label := range input.Labels {
+				task.Labels = append(task.Labels, label.Name)
+			}
+			tasks = append(tasks, task)
+			warnings = append(warnings, taskWarnings...)
+		}
+	}
+	return tasks, warnings, nil
+}
@@ -0,0 +1,91 @@
+package source
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	ttrello "txeo-tools-library/trello"
+)
+
+const olympicsBoardID = "617c56690fcb27430e740522"
+
+func newTrelloSource(t *testing.T) *TrelloSource {
+	t.Helper()
+	fixtures, err := ttrello.LoadFixtures(filepath.Join("..", "trello", "testdata", "fake"))
+	if err != nil {
+		t.Fatalf("error loading fixtures: %v", err)
+	}
+	fake := ttrello.NewFakeServer(fixtures)
+	t.Cleanup(fake.Close)
+	config := fake.Config()
+	config.Boards.CachePath = filepath.Join(t.TempDir(), "boards.json")
+	client, err := ttrello.New(config)
+	if err != nil {
+		t.Fatalf("error creating client: %v", err)
+	}
+	boards := []ttrello.BoardInfo{{ID: olympicsBoardID, Name: "Olympics"}}
+	return NewTrelloSource(ttrello.NewAPISource(client.API), boards, TrelloOptions{HoursField: "Horas"})
+}
+
+func TestTrelloSourceProjects(t *testing.T) {
+	src := newTrelloSource(t)
+
+	projects, err := src.Projects()
+	if err != nil {
+		t.Fatalf("error listing projects: %v", err)
+	}
+	want := []Project{{ID: olympicsBoardID, Name: "Olympics", Source: NameTrello}}
+	if !reflect.DeepEqual(projects, want) {
+		t.Errorf("got projects %+v, want %+v", projects, want)
+	}
+}
+
+func TestTrelloSourcePeriods(t *testing.T) {
+	src := newTrelloSource(t)
+
+	periods, err := src.Periods(olympicsBoardID)
+	if err != nil {
+		t.Fatalf("error listing periods: %v", err)
+	}
+	var keys []string
+	for _, period := range periods {
+		keys = append(keys, period.Key())
+	}
+	// The Doing list has no year and is left out
+	want := []string{"2024-09", "2024-10", "2024-11"}
+	if !reflect.DeepEqual(keys, want) {
+		t.Errorf("got periods %v, want %v", keys, want)
+	}
+}
+
+func TestTrelloSourceTasks(t *testing.T) {
+	src := newTrelloSource(t)
+
+	tasks, _, err := src.Tasks(olympicsBoardID, MonthPeriod(2024, time.October))
+	if err != nil {
+		t.Fatalf("error fetching tasks: %v", err)
+	}
+	if len(tasks) != 3 {
+		t.Fatalf("got %d tasks, want the 3 cards of Octubre 2024", len(tasks))
+	}
+	var hours float64
+	for _, task := range tasks {
+		if task.Source != NameTrello || task.Project != "Olympics" || task.SourceID == "" {
+			t.Errorf("got task %+v without its source", task)
+		}
+		hours += task.TimeForTask
+	}
+	if hours != 5 {
+		t.Errorf("got %.2fh, want 5h", hours)
+	}
+	if urgent := tasks[1]; urgent.SourceID != "6710000000000000000000c2" || !reflect.DeepEqual(urgent.Labels, []string{"Urgent"}) {
+		t.Errorf("got task %s with labels %v, want c2 labeled Urgent", urgent.SourceID, urgent.Labels)
+	}
+
+	if _, _, err := src.Tasks(olympicsBoardID, Period{Start: time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC), End: time.Date(2024, 10, 15, 0, 0, 0, 0, time.UTC)}); !errors.Is(err, ErrInvalidPeriod) {
+		t.Errorf("got error %v for half a month, want ErrInvalidPeriod", err)
+	}
+}
@@ -0,0 +1,218 @@
+package trello
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/adlio/trello"
+	"github.com/ozgio/strutil"
+)
+
+// ArchiveVersion is the version of the archives written by ExportBoard
+const ArchiveVersion = 1
+
+var (
+	ErrUnsupportedArchive = errors.New("unsupported archive version")
+	ErrArchiveExists      = errors.New("archive already exists")
+	ErrNotArchived        = errors.New("not in the archive")
+)
+
+// Archive is a snapshot of a board as it was when exported. Responses are
+// kept as Trello sent them, so loading an archive gives the same values the
+// live API gave at export time.
+type Archive struct {
+	Version      int                        `json:"version"`
+	ExportedAt   time.Time                  `json:"exportedAt"`
+	BoardID      string                     `json:"boardId"`
+	Meta         ArchiveMeta                `json:"meta"`
+	Board        json.RawMessage            `json:"board"`
+	Lists        json.RawMessage            `json:"lists"`
+	Cards        json.RawMessage            `json:"cards"`     // Open cards of the board
+	ListCards    map[string]json.RawMessage `json:"listCards"` // Open cards of every list, archived lists included
+	CustomFields json.RawMessage            `json:"customFields"`
+	Labels       json.RawMessage            `json:"labels"`
+	Members      json.RawMessage            `json:"members"`
+}
+
+// ArchiveMeta records why an archive was made
+type ArchiveMeta struct {
+	Month   string `json:"month,omitempty"`
+	Year    string `json:"year,omitempty"`
+	Invoice string `json:"invoice,omitempty"`
+	Note    string `json:"note,omitempty"`
+}
+
+// ExportBoard fetches everything a report needs from a board: lists, cards
+// with checklists and custom field values, labels, custom fields and members
+func ExportBoard(api *trello.Client, boardID string, meta ArchiveMeta) (*Archive, error) {
+	archive := &Archive{
+		Version:    ArchiveVersion,
+		ExportedAt: time.Now().UTC(),
+		BoardID:    boardID,
+		Meta:       meta,
+		ListCards:  map[string]json.RawMessage{},
+	}
+
+	requests := []struct {
+		target  *json.RawMessage
+		request func(boardID string) (string, trello.Arguments)
+	}{
+		{&archive.Board, boardRequest},
+		{&archive.Lists, listsRequest},
+		{&archive.Cards, boardCardsRequest},
+		{&archive.CustomFields, customFieldsRequest},
+		{&archive.Labels, labelsRequest},
+		{&archive.Members, membersRequest},
+	}
+	for _, request := range requests {
+		path, args := request.request(boardID)
+		if err := api.Get(path, args, request.target); err != nil {
+			return nil, fmt.Errorf("error exporting %s: %w", path, err)
+		}
+	}
+
+	lists, err := archive.GetLists(boardID)
+	if err != nil {
+		return nil, err
+	}
+	for _, list := range lists {
+		path, args := cardsRequest(list.ID)
+		var cards json.RawMessage
+		if err := api.Get(path, args, &cards); err != nil {
+			return nil, fmt.Errorf("error exporting %s: %w", path, err)
+		}
+		archive.ListCards[list.ID] = cards
+	}
+	return archive, nil
+}
+
+// Export fetches a board into an archive
+func (c Client) Export(boardID string, meta ArchiveMeta) (*Archive, error) {
+	return ExportBoard(c.API, boardID, meta)
+}
+
+// ArchiveName is the file name used for the archive of a board month, like olympics-2024-10.json.gz
+func ArchiveName(board string, period ListPeriod) string {
+	if period.Year == 0 {
+		return fmt.Sprintf("%s-%02d.json.gz", strutil.Slugify(board), period.Month)
+	}
+	return fmt.Sprintf("%s-%d-%02d.json.gz", strutil.Slugify(board), period.Year, period.Month)
+}
+
+// Save writes the archive gzipped to path. Archives are a record of the past,
+// so an existing file is never overwritten.
+func (a *Archive) Save(path string) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0444)
+	if errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("%w: %s", ErrArchiveExists, path)
+	}
+	if err != nil {
+		return err
+	}
+
+	writer := gzip.NewWriter(file)
+	writer.Name = fmt.Sprintf("%s.json", a.BoardID)
+	writer.ModTime = a.ExportedAt
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	err = encoder.Encode(a)
+	if closeErr := writer.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return fmt.Errorf("error writing archive %s: %w", path, err)
+	}
+	return nil
+}
+
+// LoadArchive reads an archive written by Save
+func LoadArchive(path string) (*Archive, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading archive %s: %w", path, err)
+	}
+	defer reader.Close()
+
+	var archive Archive
+	if err := json.NewDecoder(reader).Decode(&archive); err != nil {
+		return nil, fmt.Errorf("error decoding archive %s: %w", path, err)
+	}
+	if archive.Version < 1 || archive.Version > ArchiveVersion {
+		return nil, fmt.Errorf("%w: %s is version %d, %d is supported", ErrUnsupportedArchive, path, archive.Version, ArchiveVersion)
+	}
+	if archive.ListCards == nil {
+		archive.ListCards = map[string]json.RawMessage{}
+	}
+	return &archive, nil
+}
+
+// The archive is a Source, every call decodes fresh values
+func (a *Archive) GetBoard(boardID string) (board *trello.Board, err error) {
+	err = a.decode(boardID, "board", a.Board, &board)
+	return board, err
+}
+
+func (a *Archive) GetLists(boardID string) (lists []*trello.List, err error) {
+	err = a.decode(boardID, "lists", a.Lists, &lists)
+	return lists, err
+}
+
+func (a *Archive) GetCards(listID string) (cards []*trello.Card, err error) {
+	data, ok := a.ListCards[listID]
+	if !ok {
+		return nil, fmt.Errorf("cards of list %s: %w", listID, ErrNotArchived)
+	}
+	err = json.Unmarshal(data, &cards)
+	return cards, err
+}
+
+func (a *Archive) GetBoardCards(boardID string) (cards []*trello.Card, err error) {
+	err = a.decode(boardID, "cards", a.Cards, &cards)
+	return cards, err
+}
+
+func (a *Archive) GetCustomFields(boardID string) (customFields []*trello.CustomField, err error) {
+	err = a.decode(boardID, "custom fields", a.CustomFields, &customFields)
+	return customFields, err
+}
+
+func (a *Archive) GetLabels(boardID string) (labels []*trello.Label, err error) {
+	err = a.decode(boardID, "labels", a.Labels, &labels)
+	return labels, err
+}
+
+// GetMembers returns the members of the board when it was exported
+func (a *Archive) GetMembers(boardID string) (members []*trello.Member, err error) {
+	err = a.decode(boardID, "members", a.Members, &members)
+	return members, err
+}
+
+/* ╭──────────────────────────────────────────╮ */
+/* │              AUX FUNCTIONS               │ */
+/* ╰──────────────────────────────────────────╯ */
+func (a *Archive) decode(boardID, what string, data json.RawMessage, target interface{}) error {
+	if boardID != a.BoardID {
+		return fmt.Errorf("%s of board %s: %w", what, boardID, ErrNotArchived)
+	}
+	if len(data) == 0 {
+		return fmt.Errorf("%s of board %s: %w", what, boardID, ErrNotArchived)
+	}
+	if err := json.Unmarshal(data, target); err != nil {
+		return fmt.Errorf("error decoding %s of board %s: %w", what, boardID, err)
+	}
+	return nil
+}
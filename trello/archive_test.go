@@ -0,0 +1,107 @@
+package trello
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestArchiveRoundTrip(t *testing.T) {
+	_, client := newFakeClient(t)
+	archive, err := client.Export(olympicsBoardID, ArchiveMeta{Month: "October", Year: "2024", Invoice: "F-2024-10"})
+	if err != nil {
+		t.Fatalf("error exporting: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), ArchiveName("Olympics", ListPeriod{Month: time.October, Year: 2024}))
+	if filepath.Base(path) != "olympics-2024-10.json.gz" {
+		t.Errorf("got archive name %s", filepath.Base(path))
+	}
+	if err := archive.Save(path); err != nil {
+		t.Fatalf("error saving: %v", err)
+	}
+	if err := archive.Save(path); !errors.Is(err, ErrArchiveExists) {
+		t.Errorf("got %v saving twice, want ErrArchiveExists", err)
+	}
+
+	loaded, err := LoadArchive(path)
+	if err != nil {
+		t.Fatalf("error loading: %v", err)
+	}
+	if loaded.Version != ArchiveVersion || loaded.Meta.Invoice != "F-2024-10" || !loaded.ExportedAt.Equal(archive.ExportedAt) {
+		t.Errorf("got archive %d of %v with meta %+v", loaded.Version, loaded.ExportedAt, loaded.Meta)
+	}
+	board, err := loaded.GetBoard(olympicsBoardID)
+	if err != nil || board.Name != "Olympics" {
+		t.Errorf("got board %v (%v)", board, err)
+	}
+
+	// The archive serves the same month as the live board
+	live, err := FetchMonthLists(client, olympicsBoardID, october, year2024)
+	if err != nil {
+		t.Fatalf("error fetching the live month: %v", err)
+	}
+	archived, err := FetchMonthLists(loaded, olympicsBoardID, october, year2024)
+	if err != nil {
+		t.Fatalf("error reading the archived month: %v", err)
+	}
+	if len(archived) != 1 || len(archived[0].Cards) != len(live[0].Cards) {
+		t.Fatalf("got %+v from the archive, want %+v", archived, live)
+	}
+	for i, card := range archived[0].Cards {
+		if card.ID != live[0].Cards[i].ID || card.Name != live[0].Cards[i].Name || len(card.CustomFieldItems) != len(live[0].Cards[i].CustomFieldItems) {
+			t.Errorf("archived card %d = %s %q, want %s %q", i, card.ID, card.Name, live[0].Cards[i].ID, live[0].Cards[i].Name)
+		}
+	}
+	for name, get := range map[string]func(string) error{
+		"custom fields": func(id string) error { _, err := loaded.GetCustomFields(id); return err },
+		"labels":        func(id string) error { _, err := loaded.GetLabels(id); return err },
+		"members":       func(id string) error { _, err := loaded.GetMembers(id); return err },
+		"board cards":   func(id string) error { _, err := loaded.GetBoardCards(id); return err },
+	} {
+		if err := get(olympicsBoardID); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+		if err := get("another board"); !errors.Is(err, ErrNotArchived) {
+			t.Errorf("%s of another board: got %v, want ErrNotArchived", name, err)
+		}
+	}
+	if _, err := loaded.GetCards("unknown list"); !errors.Is(err, ErrNotArchived) {
+		t.Errorf("got %v for an unknown list, want ErrNotArchived", err)
+	}
+
+	loaded.Board = json.RawMessage(`{"name": 1}`)
+	if _, err := loaded.GetBoard(olympicsBoardID); err == nil || !strings.Contains(err.Error(), "error decoding board of board") {
+		t.Errorf("got %v for a corrupt board, want a board decoding error", err)
+	}
+}
+
+func TestLoadArchiveVersion(t *testing.T) {
+	dir := t.TempDir()
+	for _, version := range []int{0, ArchiveVersion + 1} {
+		path := filepath.Join(dir, fmt.Sprintf("v%d.json.gz", version))
+		file, err := os.Create(path)
+		if err != nil {
+			t.Fatalf("error creating archive: %v", err)
+		}
+		writer := gzip.NewWriter(file)
+		fmt.Fprintf(writer, `{"version": %d, "boardId": %q}`, version, olympicsBoardID)
+		writer.Close()
+		file.Close()
+
+		if _, err := LoadArchive(path); !errors.Is(err, ErrUnsupportedArchive) {
+			t.Errorf("version %d: got %v, want ErrUnsupportedArchive", version, err)
+		}
+	}
+
+	path := filepath.Join(dir, "plain.json.gz")
+	os.WriteFile(path, []byte(`{"version": 1}`), 0644)
+	if _, err := LoadArchive(path); err == nil {
+		t.Error("loaded an archive that is not gzipped")
+	}
+}
@@ -0,0 +1,292 @@
+package trello
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/adlio/trello"
+	"github.com/ozgio/strutil"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrUnknownBoard is returned when a board name is neither a known board nor an alias
+var ErrUnknownBoard = errors.New("unknown board")
+
+var boardIDRegex = regexp.MustCompile(`^[0-9a-f]{24}$`)
+
+// Registry used by GetBoardIDFromBoardName, set by InitTrello
+var (
+	defaultRegistryMu sync.RWMutex
+	defaultRegistry   = NewBoardRegistry()
+)
+
+// BoardInfo identifies a Trello board
+type BoardInfo struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// BoardsConfig is the content of the boards config file, aliases point to a board name or ID:
+//
+//	{"aliases": {"liv": "LivGolf", "porra": "66757258661e38a299a7e687"}}
+type BoardsConfig struct {
+	Aliases map[string]string `json:"aliases"`
+}
+
+// boardsCache is the content of the local boards cache file
+type boardsCache struct {
+	UpdatedAt time.Time   `json:"updatedAt"`
+	Boards    []BoardInfo `json:"boards"`
+}
+
+// BoardRegistryOptions tells LoadBoardRegistry where to find the boards
+type BoardRegistryOptions struct {
+	MemberID   string             // Member whose boards are discovered, "me" when empty
+	ConfigPath string             // Boards config file with aliases, optional
+	CachePath  string             // Local cache of discovered boards, DefaultBoardsCachePath() when empty
+	MaxAge     time.Duration      // Age after which the cache is refreshed from the API, 24h when zero
+	Logger     logrus.FieldLogger // Logs the failed refreshes of a stale cache, logrus.StandardLogger() when nil
+}
+
+// BoardRegistry maps board names and aliases to board IDs
+type BoardRegistry struct {
+	mu        sync.RWMutex
+	boards    map[string]BoardInfo // Keyed by slug of the board name
+	aliases   map[string]string    // Slug of the alias --> board name or ID
+	updatedAt time.Time
+	cachePath string // Cache file the registry was loaded from
+}
+
+func NewBoardRegistry() *BoardRegistry {
+	return &BoardRegistry{
+		boards:  map[string]BoardInfo{},
+		aliases: map[string]string{},
+	}
+}
+
+// LoadBoardRegistry builds a registry from the local cache, refreshing it from
+// the API when it is missing or older than MaxAge, and merges the config aliases.
+// A stale cache is kept when the refresh fails. client may be nil to work only
+// from the cache.
+func LoadBoardRegistry(client *trello.Client, options BoardRegistryOptions) (*BoardRegistry, error) {
+	if options.CachePath == "" {
+		options.CachePath = DefaultBoardsCachePath()
+	}
+	if options.MaxAge == 0 {
+		options.MaxAge = 24 * time.Hour
+	}
+
+	registry := NewBoardRegistry()
+	registry.cachePath = options.CachePath
+	cacheErr := registry.LoadCache(options.CachePath)
+	if client != nil && (cacheErr != nil || time.Since(registry.UpdatedAt()) > options.MaxAge) {
+		if err := registry.Discover(client, options.MemberID); err != nil {
+			if cacheErr != nil {
+				return nil, err
+			}
+			logger := options.Logger
+			if logger == nil {
+				logger = logrus.StandardLogger()
+			}
+			logger.WithError(err).Warn("Error refreshing the Trello boards cache, using the stale one")
+		} else if err := registry.SaveCache(options.CachePath); err != nil {
+			return nil, err
+		}
+	} else if cacheErr != nil {
+		return nil, cacheErr
+	}
+
+	if options.ConfigPath != "" {
+		if err := registry.LoadConfig(options.ConfigPath); err != nil {
+			return nil, err
+		}
+	}
+	return registry, nil
+}
+
+// DefaultBoardRegistry returns the registry used by GetBoardIDFromBoardName
+func DefaultBoardRegistry() *BoardRegistry {
+	defaultRegistryMu.RLock()
+	defer defaultRegistryMu.RUnlock()
+	return defaultRegistry
+}
+
+// SetDefaultBoardRegistry makes GetBoardIDFromBoardName resolve through registry
+func SetDefaultBoardRegistry(registry *BoardRegistry) {
+	defaultRegistryMu.Lock()
+	defer defaultRegistryMu.Unlock()
+	defaultRegistry = registry
+}
+
+// DefaultBoardsCachePath is the boards cache inside the user cache directory
+func DefaultBoardsCachePath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "txeo-tools-library", "boards.json")
+}
+
+// Discover replaces the known boards with the boards of the member, "me" when
+// memberID is empty. The aliases of the config are kept.
+func (r *BoardRegistry) Discover(client *trello.Client, memberID string) error {
+	if memberID == "" {
+		memberID = "me"
+	}
+	var boards []*trello.Board
+	path := fmt.Sprintf("members/%s/boards", memberID)
+	if err := client.Get(path, trello.Arguments{"fields": "id,name"}, &boards); err != nil {
+		return fmt.Errorf("error discovering boards of member %s: %w", memberID, err)
+	}
+
+	discovered := make(map[string]BoardInfo, len(boards))
+	for _, board := range boards {
+		discovered[strutil.Slugify(board.Name)] = BoardInfo{ID: board.ID, Name: board.Name}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.boards = discovered
+	r.updatedAt = time.Now()
+	return nil
+}
+
+// Add registers a board by hand
+func (r *BoardRegistry) Add(board BoardInfo) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.boards[strutil.Slugify(board.Name)] = board
+}
+
+// AddAlias makes alias resolve to target, a board name or ID
+func (r *BoardRegistry) AddAlias(alias, target string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.aliases[strutil.Slugify(alias)] = target
+}
+
+// LoadConfig merges the aliases of a boards config file
+func (r *BoardRegistry) LoadConfig(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading boards config %s: %w", path, err)
+	}
+	var config BoardsConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return fmt.Errorf("error parsing boards config %s: %w", path, err)
+	}
+	for alias, target := range config.Aliases {
+		r.AddAlias(alias, target)
+	}
+	return nil
+}
+
+// LoadCache adds the boards stored in the cache file
+func (r *BoardRegistry) LoadCache(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var cache boardsCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return fmt.Errorf("error parsing boards cache %s: %w", path, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, board := range cache.Boards {
+		r.boards[strutil.Slugify(board.Name)] = board
+	}
+	r.updatedAt = cache.UpdatedAt
+	return nil
+}
+
+// SaveCache stores the known boards in the cache file
+func (r *BoardRegistry) SaveCache(path string) error {
+	cache := boardsCache{UpdatedAt: r.UpdatedAt(), Boards: r.Boards()}
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// CachePath is the cache file the registry was loaded from,
+// DefaultBoardsCachePath() when it wasn't loaded by LoadBoardRegistry
+func (r *BoardRegistry) CachePath() string {
+	if r.cachePath == "" {
+		return DefaultBoardsCachePath()
+	}
+	return r.cachePath
+}
+
+// UpdatedAt is when the boards were last discovered
+func (r *BoardRegistry) UpdatedAt() time.Time {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.updatedAt
+}
+
+// Boards returns the known boards sorted by name
+func (r *BoardRegistry) Boards() []BoardInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	boards := make([]BoardInfo, 0, len(r.boards))
+	for _, board := range r.boards {
+		boards = append(boards, board)
+	}
+	sort.Slice(boards, func(i, j int) bool { return boards[i].Name < boards[j].Name })
+	return boards
+}
+
+// Names returns the names of the known boards sorted
+func (r *BoardRegistry) Names() []string {
+	var names []string
+	for _, board := range r.Boards() {
+		names = append(names, board.Name)
+	}
+	return names
+}
+
+// Resolve finds a board by name, alias or ID
+func (r *BoardRegistry) Resolve(name string) (BoardInfo, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	slug := strutil.Slugify(name)
+	if target, ok := r.aliases[slug]; ok {
+		slug = strutil.Slugify(target)
+		name = target
+	}
+	if board, ok := r.boards[slug]; ok {
+		return board, nil
+	}
+	for _, board := range r.boards {
+		if board.ID == name {
+			return board, nil
+		}
+	}
+	if boardIDRegex.MatchString(name) {
+		return BoardInfo{ID: name}, nil
+	}
+	return BoardInfo{}, fmt.Errorf("%w: %s", ErrUnknownBoard, name)
+}
+
+// ID returns the ID of a board given its name, alias or ID
+func (r *BoardRegistry) ID(name string) (string, error) {
+	board, err := r.Resolve(name)
+	if err != nil {
+		return "", err
+	}
+	return board.ID, nil
+}
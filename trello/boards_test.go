@@ -0,0 +1,102 @@
+package trello
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/adlio/trello"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+func TestDefaultBoardRegistry(t *testing.T) {
+	previous := DefaultBoardRegistry()
+	t.Cleanup(func() { SetDefaultBoardRegistry(previous) })
+
+	registry := NewBoardRegistry()
+	registry.Add(BoardInfo{ID: "617c56690fcb27430e740522", Name: "Olympics"})
+	registry.AddAlias("jjoo", "Olympics")
+
+	// Replaced while other goroutines resolve boards, run with -race
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := GetBoardIDFromBoardName("jjoo")
+			if err != nil && !errors.Is(err, ErrUnknownBoard) {
+				t.Errorf("unexpected error resolving a board: %v", err)
+			}
+		}()
+	}
+	SetDefaultBoardRegistry(registry)
+	wg.Wait()
+
+	if id, err := GetBoardIDFromBoardName("jjoo"); err != nil || id != "617c56690fcb27430e740522" {
+		t.Errorf("jjoo resolved to %q (err %v), want the Olympics board", id, err)
+	}
+	if _, err := GetBoardIDFromBoardName("LivGolf"); !errors.Is(err, ErrUnknownBoard) {
+		t.Errorf("got %v for an unknown board, want ErrUnknownBoard", err)
+	}
+}
+
+func TestLoadBoardRegistryKeepsStaleCache(t *testing.T) {
+	fixtures := loadFakeFixtures(t)
+	delete(fixtures, "members/me/boards") // Discovering the boards fails
+	fake := NewFakeServer(fixtures)
+	t.Cleanup(fake.Close)
+	api := trello.NewClient("fake-key", "fake-token")
+	api.BaseURL, api.Client = fake.URL, fake.Client()
+
+	cachePath := filepath.Join(t.TempDir(), "boards.json")
+	logger, hook := test.NewNullLogger()
+	options := BoardRegistryOptions{CachePath: cachePath, Logger: logger}
+	if _, err := LoadBoardRegistry(api, options); err == nil {
+		t.Fatal("expected an error without a cache to fall back to")
+	}
+
+	writeBoardsCache(t, cachePath, time.Now().Add(-48*time.Hour), BoardInfo{ID: olympicsBoardID, Name: "Olympics"})
+	registry, err := LoadBoardRegistry(api, options)
+	if err != nil {
+		t.Fatalf("error loading the stale cache: %v", err)
+	}
+	if id, err := registry.ID("Olympics"); err != nil || id != olympicsBoardID {
+		t.Errorf("Olympics resolved to %q (err %v), want the cached board", id, err)
+	}
+	if entry := hook.LastEntry(); entry == nil || entry.Level != logrus.WarnLevel {
+		t.Errorf("got log entry %+v, want a warning about the failed refresh", entry)
+	}
+}
+
+func TestDiscoverReplacesBoards(t *testing.T) {
+	_, client := newFakeClient(t)
+	registry := NewBoardRegistry()
+	registry.Add(BoardInfo{ID: "66462e4bca554f21f82b8e4a", Name: "LivGolf"}) // Left or renamed since
+	registry.AddAlias("jjoo", "Olympics")
+
+	if err := registry.Discover(client.API, "me"); err != nil {
+		t.Fatalf("error discovering boards: %v", err)
+	}
+	if _, err := registry.ID("LivGolf"); !errors.Is(err, ErrUnknownBoard) {
+		t.Errorf("got %v for a board no longer discovered, want ErrUnknownBoard", err)
+	}
+	if id, err := registry.ID("jjoo"); err != nil || id != olympicsBoardID {
+		t.Errorf("jjoo resolved to %q (err %v), want the Olympics board", id, err)
+	}
+}
+
+func writeBoardsCache(t *testing.T, path string, updatedAt time.Time, boards ...BoardInfo) {
+	t.Helper()
+	data, err := json.Marshal(boardsCache{UpdatedAt: updatedAt, Boards: boards})
+	if err != nil {
+		t.Fatalf("error encoding the boards cache: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("error writing the boards cache: %v", err)
+	}
+}
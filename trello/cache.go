@@ -0,0 +1,318 @@
+package trello
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/adlio/trello"
+)
+
+// DefaultCacheTTL is how long cached data is served without asking Trello
+const DefaultCacheTTL = time.Hour
+
+// ErrNotCached is returned in offline mode when the data was never cached
+var ErrNotCached = errors.New("not available in the offline cache")
+
+// CacheOptions configures the local cache
+type CacheOptions struct {
+	Dir     string        // DefaultCacheDir() when empty
+	TTL     time.Duration // DefaultCacheTTL when zero
+	Offline bool          // Serve only from the cache, never call Trello
+}
+
+// RegisterCacheFlags adds --offline, --cache-dir and --cache-ttl to a flag set
+func RegisterCacheFlags(flags *flag.FlagSet, options *CacheOptions) {
+	flags.BoolVar(&options.Offline, "offline", options.Offline, "serve Trello data only from the local cache")
+	flags.StringVar(&options.Dir, "cache-dir", options.Dir, "directory of the local Trello cache")
+	flags.DurationVar(&options.TTL, "cache-ttl", options.TTL, "time cached Trello data is used before fetching it again")
+}
+
+// DefaultCacheDir is the Trello cache inside the user cache directory
+func DefaultCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "txeo-tools-library", "trello")
+}
+
+// Cache is a Source that keeps every response on disk, keyed by board and list
+type Cache struct {
+	api     *trello.Client
+	dir     string
+	ttl     time.Duration
+	offline bool
+}
+
+// cacheEntry is a cached response with the activity it reflects
+type cacheEntry struct {
+	FetchedAt        time.Time       `json:"fetchedAt"`
+	DateLastActivity *time.Time      `json:"dateLastActivity,omitempty"` // Latest card activity, for card lists
+	Count            int             `json:"count"`                      // Number of cards, for card lists
+	Data             json.RawMessage `json:"data"`
+}
+
+// CacheRefresh tells what an incremental refresh did
+type CacheRefresh struct {
+	Lists      int      // Lists in the board
+	Refreshed  []string // IDs of the lists whose cards were fetched again
+	Unchanged  int      // Lists served from the previous snapshot
+	BoardCards bool     // The cards of the whole board were fetched again
+}
+
+// NewCache wraps the API with the local cache, api may be nil in offline mode
+func NewCache(api *trello.Client, options CacheOptions) *Cache {
+	if options.Dir == "" {
+		options.Dir = DefaultCacheDir()
+	}
+	if options.TTL == 0 {
+		options.TTL = DefaultCacheTTL
+	}
+	return &Cache{api: api, dir: options.Dir, ttl: options.TTL, offline: options.Offline || api == nil}
+}
+
+func (c *Cache) GetBoard(boardID string) (board *trello.Board, err error) {
+	path, args := boardRequest(boardID)
+	err = c.get(filepath.Join("boards", boardID, "board.json"), path, args, &board)
+	if board != nil && c.api != nil {
+		board.SetClient(c.api)
+	}
+	return board, err
+}
+
+func (c *Cache) GetLists(boardID string) (lists []*trello.List, err error) {
+	path, args := listsRequest(boardID)
+	err = c.get(filepath.Join("boards", boardID, "lists.json"), path, args, &lists)
+	for _, list := range lists {
+		if c.api != nil {
+			list.SetClient(c.api)
+		}
+	}
+	return lists, err
+}
+
+func (c *Cache) GetCards(listID string) (cards []*trello.Card, err error) {
+	path, args := cardsRequest(listID)
+	err = c.get(filepath.Join("lists", listID, "cards.json"), path, args, &cards)
+	c.setCardsClient(cards)
+	return cards, err
+}
+
+func (c *Cache) GetBoardCards(boardID string) (cards []*trello.Card, err error) {
+	path, args := boardCardsRequest(boardID)
+	err = c.get(filepath.Join("boards", boardID, "cards.json"), path, args, &cards)
+	c.setCardsClient(cards)
+	return cards, err
+}
+
+func (c *Cache) GetCustomFields(boardID string) (customFields []*trello.CustomField, err error) {
+	path, args := customFieldsRequest(boardID)
+	err = c.get(filepath.Join("boards", boardID, "custom_fields.json"), path, args, &customFields)
+	return customFields, err
+}
+
+func (c *Cache) GetLabels(boardID string) (labels []*trello.Label, err error) {
+	path, args := labelsRequest(boardID)
+	err = c.get(filepath.Join("boards", boardID, "labels.json"), path, args, &labels)
+	for _, label := range labels {
+		if c.api != nil {
+			label.SetClient(c.api)
+		}
+	}
+	return labels, err
+}
+
+// Refresh updates the snapshot of a board: the board, its lists, labels and
+// custom fields are fetched again, and the cards only for the lists (and the
+// whole board) whose latest card activity or number of cards changed. The
+// cards of archived lists are fetched once, as they aren't in the activity.
+func (c *Cache) Refresh(boardID string) (CacheRefresh, error) {
+	var refresh CacheRefresh
+	if c.offline {
+		return refresh, fmt.Errorf("can't refresh board %s: %w", boardID, ErrNotCached)
+	}
+
+	path, args := boardRequest(boardID)
+	if err := c.fetch(filepath.Join("boards", boardID, "board.json"), path, args, &trello.Board{}); err != nil {
+		return refresh, err
+	}
+	path, args = labelsRequest(boardID)
+	if err := c.fetch(filepath.Join("boards", boardID, "labels.json"), path, args, &[]*trello.Label{}); err != nil {
+		return refresh, err
+	}
+	path, args = customFieldsRequest(boardID)
+	if err := c.fetch(filepath.Join("boards", boardID, "custom_fields.json"), path, args, &[]*trello.CustomField{}); err != nil {
+		return refresh, err
+	}
+	path, args = listsRequest(boardID)
+	var lists []*trello.List
+	if err := c.fetch(filepath.Join("boards", boardID, "lists.json"), path, args, &lists); err != nil {
+		return refresh, err
+	}
+	refresh.Lists = len(lists)
+
+	// Only the list and activity of each card, to find what changed
+	var activity []*trello.Card
+	err := c.api.Get(fmt.Sprintf("boards/%s/cards", boardID), trello.Arguments{"fields": "idList,dateLastActivity"}, &activity)
+	if err != nil {
+		return refresh, fmt.Errorf("error fetching activity of board %s: %w", boardID, err)
+	}
+	latest, counts := cardsActivityByList(activity)
+
+	for _, list := range lists {
+		key := filepath.Join("lists", list.ID, "cards.json")
+		entry, err := c.read(key)
+		if err == nil && (list.Closed || entry.Count == counts[list.ID] && sameTime(entry.DateLastActivity, latest[list.ID])) {
+			refresh.Unchanged++
+			continue
+		}
+		path, args := cardsRequest(list.ID)
+		if err := c.fetch(key, path, args, &[]*trello.Card{}); err != nil {
+			return refresh, err
+		}
+		refresh.Refreshed = append(refresh.Refreshed, list.ID)
+	}
+
+	// The board cards, when they were cached and changed
+	key := filepath.Join("boards", boardID, "cards.json")
+	if entry, err := c.read(key); err == nil && (entry.Count != len(activity) || !sameTime(entry.DateLastActivity, latestActivity(latest))) {
+		path, args := boardCardsRequest(boardID)
+		if err := c.fetch(key, path, args, &[]*trello.Card{}); err != nil {
+			return refresh, err
+		}
+		refresh.BoardCards = true
+	}
+	return refresh, nil
+}
+
+/* ╭──────────────────────────────────────────╮ */
+/* │              AUX FUNCTIONS               │ */
+/* ╰──────────────────────────────────────────╯ */
+// get serves key from the cache when fresh (or when offline) and fetches it otherwise
+func (c *Cache) get(key, path string, args trello.Arguments, target interface{}) error {
+	entry, err := c.read(key)
+	if err == nil && (c.offline || time.Since(entry.FetchedAt) < c.ttl) {
+		return json.Unmarshal(entry.Data, target)
+	}
+	if c.offline {
+		if errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("%s: %w", path, ErrNotCached)
+		}
+		return err
+	}
+	return c.fetch(key, path, args, target)
+}
+
+// fetch asks Trello for path and stores the raw response under key
+func (c *Cache) fetch(key, path string, args trello.Arguments, target interface{}) error {
+	var data json.RawMessage
+	if err := c.api.Get(path, args, &data); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, target); err != nil {
+		return err
+	}
+
+	entry := cacheEntry{FetchedAt: time.Now(), Data: data}
+	if cards, ok := target.(*[]*trello.Card); ok {
+		entry.Count = len(*cards)
+		latest, _ := cardsActivityByList(*cards)
+		entry.DateLastActivity = latestActivity(latest)
+	}
+	return c.write(key, entry)
+}
+
+func (c *Cache) read(key string) (cacheEntry, error) {
+	var entry cacheEntry
+	data, err := os.ReadFile(filepath.Join(c.dir, key))
+	if err != nil {
+		return entry, err
+	}
+	err = json.Unmarshal(data, &entry)
+	return entry, err
+}
+
+func (c *Cache) write(key string, entry cacheEntry) error {
+	path := filepath.Join(c.dir, key)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	// Write and rename so an interrupted run never leaves a broken entry,
+	// nor two runs write the same temporary file
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+func (c *Cache) setCardsClient(cards []*trello.Card) {
+	if c.api == nil {
+		return
+	}
+	for _, card := range cards {
+		card.SetClient(c.api)
+	}
+}
+
+// cardsActivityByList returns the latest activity and the number of cards of each list
+func cardsActivityByList(cards []*trello.Card) (map[string]*time.Time, map[string]int) {
+	latest := map[string]*time.Time{}
+	counts := map[string]int{}
+	for _, card := range cards {
+		counts[card.IDList]++
+		if card.DateLastActivity == nil {
+			continue
+		}
+		if current, ok := latest[card.IDList]; !ok || card.DateLastActivity.After(*current) {
+			latest[card.IDList] = card.DateLastActivity
+		}
+	}
+	return latest, counts
+}
+
+// latestActivity returns the latest of the activities of the lists, nil when there is none
+func latestActivity(latest map[string]*time.Time) *time.Time {
+	var result *time.Time
+	for _, activity := range latest {
+		if result == nil || activity.After(*result) {
+			result = activity
+		}
+	}
+	return result
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(*b)
+}
+
+// Cached returns a Source serving the client data through the local cache
+func (c Client) Cached(options CacheOptions) *Cache {
+	return NewCache(c.API, options)
+}
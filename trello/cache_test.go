@@ -0,0 +1,100 @@
+package trello
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const archivedListID = "6710000000000000000000a1"
+
+// countRequests counts the requests sent to the fake server
+func countRequests(fake *FakeServer, request string) int {
+	count := 0
+	for _, got := range fake.Requests() {
+		if got == request {
+			count++
+		}
+	}
+	return count
+}
+
+func TestCacheRefresh(t *testing.T) {
+	fake, client := newFakeClient(t)
+	fixtures := loadFakeFixtures(t)
+	// The archived list keeps a card the board cards don't list
+	fake.Set("lists/"+archivedListID+"/cards", []map[string]interface{}{
+		{"id": "6710000000000000000000c9", "idList": archivedListID, "name": "Old card", "dateLastActivity": "2024-09-01T10:00:00.000Z"},
+	})
+	dir := t.TempDir()
+	cache := client.Cached(CacheOptions{Dir: dir})
+
+	if _, err := cache.GetBoardCards(olympicsBoardID); err != nil {
+		t.Fatalf("error caching the board cards: %v", err)
+	}
+	refresh, err := cache.Refresh(olympicsBoardID)
+	if err != nil {
+		t.Fatalf("error refreshing: %v", err)
+	}
+	if refresh.Lists != 4 || len(refresh.Refreshed) != 4 || refresh.BoardCards {
+		t.Errorf("first refresh = %+v, want the 4 lists fetched and the board cards kept", refresh)
+	}
+
+	refresh, err = cache.Refresh(olympicsBoardID)
+	if err != nil {
+		t.Fatalf("error refreshing: %v", err)
+	}
+	if len(refresh.Refreshed) != 0 || refresh.Unchanged != 4 || refresh.BoardCards {
+		t.Errorf("second refresh = %+v, want nothing fetched again", refresh)
+	}
+	if got := countRequests(fake, "GET lists/"+archivedListID+"/cards"); got != 1 {
+		t.Errorf("archived list cards fetched %d times, want once", got)
+	}
+
+	// A card renamed in October and a new label
+	var cards []map[string]interface{}
+	json.Unmarshal(fixtures["boards/"+olympicsBoardID+"/cards"], &cards)
+	cards[0]["name"] = "Weekly call with the IOC team"
+	cards[0]["dateLastActivity"] = "2024-11-20T09:00:00.000Z"
+	fake.Set("boards/"+olympicsBoardID+"/cards", cards)
+	var labels []map[string]interface{}
+	json.Unmarshal(fixtures["boards/"+olympicsBoardID+"/labels"], &labels)
+	labels = append(labels, map[string]interface{}{"id": meetingsLabelID, "idBoard": olympicsBoardID, "name": "📅 Catchups / Meetings", "color": "blue"})
+	fake.Set("boards/"+olympicsBoardID+"/labels", labels)
+
+	refresh, err = cache.Refresh(olympicsBoardID)
+	if err != nil {
+		t.Fatalf("error refreshing: %v", err)
+	}
+	if len(refresh.Refreshed) != 1 || refresh.Refreshed[0] != "6710000000000000000000a2" || !refresh.BoardCards {
+		t.Errorf("third refresh = %+v, want Octubre 2024 and the board cards fetched again", refresh)
+	}
+
+	// Served from the refreshed snapshot, without asking Trello
+	offline := NewCache(nil, CacheOptions{Dir: dir})
+	boardCards, err := offline.GetBoardCards(olympicsBoardID)
+	if err != nil || boardCards[0].Name != "Weekly call with the IOC team" {
+		t.Errorf("offline board cards = %v (%v), want the renamed card", boardCards, err)
+	}
+	listCards, err := offline.GetCards("6710000000000000000000a2")
+	if err != nil || listCards[0].Name != "Weekly call with the IOC team" {
+		t.Errorf("offline list cards = %v (%v), want the renamed card", listCards, err)
+	}
+	boardLabels, err := offline.GetLabels(olympicsBoardID)
+	if err != nil || len(boardLabels) != len(labels) {
+		t.Errorf("offline labels = %v (%v), want %d labels", boardLabels, err, len(labels))
+	}
+	if _, err := offline.Refresh(olympicsBoardID); !errors.Is(err, ErrNotCached) {
+		t.Errorf("offline refresh error = %v, want ErrNotCached", err)
+	}
+
+	filepath.WalkDir(dir, func(path string, entry os.DirEntry, err error) error {
+		if err == nil && strings.HasSuffix(path, ".tmp") {
+			t.Errorf("leftover temporary file %s", path)
+		}
+		return err
+	})
+}
@@ -0,0 +1,87 @@
+package trello
+
+import (
+	"time"
+)
+
+// WorkCalendar tells which hours count as working time
+type WorkCalendar struct {
+	Location *time.Location // time.Local when nil
+	DayStart time.Duration  // Start of the working day from midnight
+	DayEnd   time.Duration  // End of the working day from midnight
+	Weekdays []time.Weekday // Working days of the week
+	Holidays []string       // Days off as 2006-01-02
+}
+
+// DefaultWorkCalendar works Monday to Friday from 9:00 to 18:00, local time
+func DefaultWorkCalendar() WorkCalendar {
+	return WorkCalendar{
+		DayStart: 9 * time.Hour,
+		DayEnd:   18 * time.Hour,
+		Weekdays: []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+	}
+}
+
+// IsWorkday tells whether day is a working day, not a weekend or holiday
+func (w WorkCalendar) IsWorkday(day time.Time) bool {
+	day = day.In(w.location())
+	for _, holiday := range w.Holidays {
+		if day.Format("2006-01-02") == holiday {
+			return false
+		}
+	}
+	for _, weekday := range w.Weekdays {
+		if day.Weekday() == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// Between returns the working time from from to to
+func (w WorkCalendar) Between(from, to time.Time) time.Duration {
+	if !to.After(from) {
+		return 0
+	}
+	location := w.location()
+	from, to = from.In(location), to.In(location)
+
+	var total time.Duration
+	for day := midnight(from); day.Before(to); day = day.AddDate(0, 0, 1) {
+		if !w.IsWorkday(day) {
+			continue
+		}
+		start, end := clock(day, w.DayStart), clock(day, w.DayEnd)
+		if from.After(start) {
+			start = from
+		}
+		if to.Before(end) {
+			end = to
+		}
+		if end.After(start) {
+			total += end.Sub(start)
+		}
+	}
+	return total
+}
+
+/* ╭──────────────────────────────────────────╮ */
+/* │              AUX FUNCTIONS               │ */
+/* ╰──────────────────────────────────────────╯ */
+func (w WorkCalendar) location() *time.Location {
+	if w.Location == nil {
+		return time.Local
+	}
+	return w.Location
+}
+
+// clock returns the wall clock time offset from the midnight of day, which
+// isn't midnight plus offset on the days the clocks change
+func clock(day time.Time, offset time.Duration) time.Time {
+	hours, minutes, seconds := int(offset/time.Hour), int(offset%time.Hour/time.Minute), int(offset%time.Minute/time.Second)
+	return time.Date(day.Year(), day.Month(), day.Day(), hours, minutes, seconds, 0, day.Location())
+}
+
+func midnight(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,139 @@
+package trello
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+
+	"github.com/adlio/trello"
+	"github.com/joho/godotenv"
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultMemberID is the member used when Config.MemberID is empty, the owner of the token
+const DefaultMemberID = "me"
+
+var (
+	ErrMissingCredentials = errors.New("missing Trello app key or token")
+	ErrMemberNotFound     = errors.New("Trello member not found")
+)
+
+// Config holds everything needed to talk to Trello
+type Config struct {
+	AppKey     string
+	Token      string
+	MemberID   string             // Member whose boards are used, DefaultMemberID when empty
+	BaseURL    string             // trello.DefaultBaseURL when empty
+	HTTPClient *http.Client       // A client with the rate limited and retrying Transport when nil
+	Logger     logrus.FieldLogger // Requests are logged at debug level, nothing is logged when nil
+	Boards     BoardRegistryOptions
+}
+
+// Client is a Trello client bound to a member and its boards
+type Client struct {
+	API    *trello.Client
+	Member *trello.Member
+	Boards *BoardRegistry
+	Logger logrus.FieldLogger
+}
+
+// ConfigFromEnv reads the config from TRELLO_APP_KEY, TRELLO_TOKEN, TRELLO_MEMBER,
+// TRELLO_BASE_URL and TRELLO_BOARDS_CONFIG, loading the .env file when there is one.
+func ConfigFromEnv() (Config, error) {
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return Config{}, fmt.Errorf("error loading .env file: %w", err)
+	}
+	config := Config{
+		AppKey:   os.Getenv("TRELLO_APP_KEY"),
+		Token:    os.Getenv("TRELLO_TOKEN"),
+		MemberID: os.Getenv("TRELLO_MEMBER"),
+		BaseURL:  os.Getenv("TRELLO_BASE_URL"),
+		Boards: BoardRegistryOptions{
+			ConfigPath: os.Getenv("TRELLO_BOARDS_CONFIG"),
+		},
+	}
+	if config.AppKey == "" || config.Token == "" {
+		return config, ErrMissingCredentials
+	}
+	return config, nil
+}
+
+// New creates a client, checking the credentials by fetching the configured member
+// and loading its boards registry.
+func New(config Config) (Client, error) {
+	if config.AppKey == "" || config.Token == "" {
+		return Client{}, ErrMissingCredentials
+	}
+	if config.MemberID == "" {
+		config.MemberID = DefaultMemberID
+	}
+
+	api := trello.NewClient(config.AppKey, config.Token)
+	if config.BaseURL != "" {
+		api.BaseURL = config.BaseURL
+	}
+	if config.HTTPClient != nil {
+		api.Client = config.HTTPClient
+	} else {
+		api.Client = &http.Client{Transport: NewTransport(TransportOptions{Logger: config.Logger})}
+	}
+	if config.Logger != nil {
+		api.Logger = config.Logger
+	}
+
+	member, err := api.GetMember(config.MemberID, trello.Defaults())
+	if err != nil {
+		return Client{}, fmt.Errorf("error fetching Trello member %s: %w", config.MemberID, err)
+	}
+	if member == nil {
+		return Client{}, fmt.Errorf("%w: %s", ErrMemberNotFound, config.MemberID)
+	}
+
+	boardOptions := config.Boards
+	boardOptions.MemberID = member.ID
+	if boardOptions.Logger == nil {
+		boardOptions.Logger = config.Logger
+	}
+	boards, err := LoadBoardRegistry(api, boardOptions)
+	if err != nil {
+		return Client{}, err
+	}
+
+	return Client{API: api, Member: member, Boards: boards, Logger: config.Logger}, nil
+}
+
+// Board fetches a board given its name, alias or ID, rediscovering the member
+// boards once when the name is unknown.
+func (c Client) Board(name string) (*trello.Board, error) {
+	boardID, err := c.Boards.ID(name)
+	if errors.Is(err, ErrUnknownBoard) {
+		if discoverErr := c.Boards.Discover(c.API, c.Member.ID); discoverErr != nil {
+			return nil, discoverErr
+		}
+		if saveErr := c.Boards.SaveCache(c.Boards.CachePath()); saveErr != nil {
+			c.log().WithError(saveErr).Warn("Error saving the Trello boards cache")
+		}
+		boardID, err = c.Boards.ID(name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	board, err := c.API.GetBoard(boardID, trello.Defaults())
+	if err != nil {
+		return nil, fmt.Errorf("error fetching Trello board %s: %w", name, err)
+	}
+	return board, nil
+}
+
+/* ╭──────────────────────────────────────────╮ */
+/* │              AUX FUNCTIONS               │ */
+/* ╰──────────────────────────────────────────╯ */
+func (c Client) log() logrus.FieldLogger {
+	if c.Logger == nil {
+		return logrus.StandardLogger()
+	}
+	return c.Logger
+}
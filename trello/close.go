@@ -0,0 +1,387 @@
+package trello
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"txeo-tools-library/models"
+
+	"github.com/adlio/trello"
+)
+
+var (
+	// Month names as written in list names, to name the next month the same way
+	englishMonthNames = []string{"january", "february", "march", "april", "may", "june", "july", "august", "september", "october", "november", "december"}
+	spanishMonthNames = []string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"}
+	englishMonthAbbrs = []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}
+	spanishMonthAbbrs = []string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sep", "oct", "nov", "dic"}
+
+	wordRegex         = regexp.MustCompile(`\pL+`)
+	fullYearRegex     = regexp.MustCompile(`\b\d{4}\b`)
+	shortYearRegex    = regexp.MustCompile(`['’]\d{2}\b`)
+	numericMonthRegex = regexp.MustCompile(`^(\s*)(\d{4})([-/])(\d{1,2})(\s*)$|^(\s*)(\d{1,2})([-/])(\d{4})(\s*)$`)
+)
+
+// CloseMonthOptions tunes CloseMonth
+type CloseMonthOptions struct {
+	Fields       FieldNames // DefaultFieldNames when empty
+	Comment      bool       // Comment the invoice instead of setting the invoice custom field
+	CloseList    bool       // Close (archive) the list instead of moving it to the bottom of the board
+	ArchiveCards bool       // Archive the cards of the list too
+	Template     string     // Name of the list copied as next month's list, an empty list is created when empty
+	DryRun       bool       // Only report what would change
+}
+
+// MonthClose summarizes a CloseMonth run
+type MonthClose struct {
+	DryRun    bool
+	Invoice   string
+	List      string // Name of the closed month list
+	NextList  string // Name of the list created for next month
+	Cards     int
+	Stamped   int // Cards with the invoice set in the custom field
+	Commented int // Cards with the invoice written as a comment
+	Skipped   int // Cards that already had the invoice
+	Archived  int // Cards archived
+	Changes   []string
+}
+
+// CloseMonth closes the month list of a board once it has been invoiced: it
+// writes the invoice number on every card (custom field or comment), moves
+// the list to the bottom of the board or closes it, and creates next month's
+// list, copying the template list when there is one.
+func (c Client) CloseMonth(boardID string, month models.Month, year models.Year, invoice string, options CloseMonthOptions) (MonthClose, error) {
+	result := MonthClose{DryRun: options.DryRun, Invoice: invoice}
+	if invoice == "" {
+		return result, fmt.Errorf("missing invoice number")
+	}
+	if month.IsAllYear() {
+		return result, fmt.Errorf("%w: a single month must be closed", ErrInvalidMonth)
+	}
+	if options.Fields == (FieldNames{}) {
+		options.Fields = DefaultFieldNames
+	}
+
+	src := NewAPISource(c.API)
+	lists, err := src.GetLists(boardID)
+	if err != nil {
+		return result, fmt.Errorf("error fetching lists of board %s: %w", boardID, err)
+	}
+	monthLists, err := ResolveMonthLists(lists, month, year)
+	if err != nil {
+		return result, err
+	}
+	monthList := monthLists[0]
+	monthList.List.SetClient(c.API)
+	result.List = monthList.List.Name
+
+	cards, err := src.GetCards(monthList.List.ID)
+	if err != nil {
+		return result, fmt.Errorf("error fetching cards of list %s: %w", monthList.List.Name, err)
+	}
+	definitions, err := src.GetCustomFields(boardID)
+	if err != nil {
+		return result, fmt.Errorf("error fetching custom fields of board %s: %w", boardID, err)
+	}
+
+	invoiceField := findCustomField(definitions, options.Fields.Invoice)
+	if !options.Comment && invoiceField == nil {
+		result.Changes = append(result.Changes, fmt.Sprintf("board has no %q custom field, commenting the invoice instead", options.Fields.Invoice))
+		options.Comment = true
+	}
+
+	// Invoice
+	for _, card := range cards {
+		result.Cards++
+		if err := c.stampInvoice(card, definitions, invoiceField, invoice, options, &result); err != nil {
+			return result, err
+		}
+	}
+
+	// Next month's list, created before touching the closed one so it takes its place
+	nextName, err := NextListName(monthList.List.Name)
+	if err != nil {
+		return result, err
+	}
+	if existing := findList(lists, nextName); existing != nil {
+		result.Changes = append(result.Changes, fmt.Sprintf("list %q already exists", nextName))
+	} else {
+		args := trello.Arguments{"pos": strconv.FormatFloat(float64(monthList.List.Pos), 'f', -1, 32)}
+		if options.Template != "" {
+			template := findList(lists, options.Template)
+			if template == nil {
+				return result, fmt.Errorf("template list %q not found", options.Template)
+			}
+			args["idListSource"] = template.ID
+			result.Changes = append(result.Changes, fmt.Sprintf("create list %q from %q", nextName, template.Name))
+		} else {
+			result.Changes = append(result.Changes, fmt.Sprintf("create list %q", nextName))
+		}
+		if !options.DryRun {
+			if _, err := c.API.CreateList(&trello.Board{ID: boardID}, nextName, args); err != nil {
+				return result, fmt.Errorf("error creating list %q: %w", nextName, err)
+			}
+		}
+		result.NextList = nextName
+	}
+
+	// Cards
+	if options.ArchiveCards {
+		for _, card := range cards {
+			if card.Closed {
+				continue
+			}
+			result.Archived++
+			result.Changes = append(result.Changes, fmt.Sprintf("archive card %q", card.Name))
+			if options.DryRun {
+				continue
+			}
+			card.SetClient(c.API)
+			if err := card.Archive(); err != nil {
+				return result, fmt.Errorf("error archiving card %q: %w", card.Name, err)
+			}
+		}
+	}
+
+	// Month list
+	if options.CloseList {
+		result.Changes = append(result.Changes, fmt.Sprintf("close list %q", monthList.List.Name))
+		if !options.DryRun {
+			if err := monthList.List.Archive(); err != nil {
+				return result, fmt.Errorf("error closing list %q: %w", monthList.List.Name, err)
+			}
+		}
+	} else {
+		result.Changes = append(result.Changes, fmt.Sprintf("move list %q to the bottom of the board", monthList.List.Name))
+		if !options.DryRun {
+			if err := monthList.List.Update(trello.Arguments{"pos": "bottom"}); err != nil {
+				return result, fmt.Errorf("error moving list %q: %w", monthList.List.Name, err)
+			}
+		}
+	}
+	return result, nil
+}
+
+// NextListName names the list of the month after the one in name following
+// the same convention: "Octubre 2024" -> "Noviembre 2024", "2024-12" -> "2025-01",
+// "Dec '24" -> "Jan '25", "October" -> "November".
+func NextListName(name string) (string, error) {
+	period, ok := ParseListPeriod(name)
+	if !ok {
+		return "", fmt.Errorf("%w: list %q has no month", ErrInvalidMonth, name)
+	}
+	next := time.Date(period.Year, period.Month+1, 1, 0, 0, 0, 0, time.UTC)
+	if period.Year == 0 {
+		next = time.Date(2000, period.Month+1, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	if groups := numericMonthRegex.FindStringSubmatch(name); groups != nil {
+		if groups[2] != "" {
+			return fmt.Sprintf("%s%d%s%s%s", groups[1], next.Year(), groups[3], padMonth(next.Month(), groups[4]), groups[5]), nil
+		}
+		return fmt.Sprintf("%s%s%s%d%s", groups[6], padMonth(next.Month(), groups[7]), groups[8], next.Year(), groups[10]), nil
+	}
+
+	replaced := false
+	result := wordRegex.ReplaceAllStringFunc(name, func(word string) string {
+		if replaced {
+			return word
+		}
+		nextWord, ok := nextMonthWord(word, next.Month())
+		if !ok {
+			return word
+		}
+		replaced = true
+		return nextWord
+	})
+	if period.Year == 0 {
+		return result, nil
+	}
+	result = fullYearRegex.ReplaceAllLiteralString(result, strconv.Itoa(next.Year()))
+	return shortYearRegex.ReplaceAllStringFunc(result, func(year string) string {
+		return year[:len(year)-2] + fmt.Sprintf("%02d", next.Year()%100)
+	}), nil
+}
+
+/* ╭──────────────────────────────────────────╮ */
+/* │              AUX FUNCTIONS               │ */
+/* ╰──────────────────────────────────────────╯ */
+func (c Client) stampInvoice(card *trello.Card, definitions []*trello.CustomField, invoiceField *trello.CustomField, invoice string, options CloseMonthOptions, result *MonthClose) error {
+	if options.Comment {
+		commented, err := c.hasInvoiceComment(card, invoice)
+		if err != nil {
+			return err
+		}
+		if commented {
+			result.Skipped++
+			return nil
+		}
+		result.Commented++
+		result.Changes = append(result.Changes, fmt.Sprintf("comment invoice %s on %q", invoice, card.Name))
+		if options.DryRun {
+			return nil
+		}
+		card.SetClient(c.API)
+		if _, err := card.AddComment(invoiceComment(invoice)); err != nil {
+			return fmt.Errorf("error commenting invoice on card %q: %w", card.Name, err)
+		}
+		return nil
+	}
+
+	if current, ok := DecodeCustomFields(card, definitions).Get(invoiceField.Name); ok && current.String() == invoice {
+		result.Skipped++
+		return nil
+	}
+
+	var value map[string]string
+	switch invoiceField.Type {
+	case FieldTypeText:
+		value = map[string]string{"text": invoice}
+	case FieldTypeNumber:
+		if _, err := strconv.ParseFloat(invoice, 64); err != nil {
+			return fmt.Errorf("invoice %q doesn't fit the number field %q", invoice, invoiceField.Name)
+		}
+		value = map[string]string{"number": invoice}
+	default:
+		return fmt.Errorf("custom field %q of type %s can't hold an invoice number", invoiceField.Name, invoiceField.Type)
+	}
+
+	result.Stamped++
+	result.Changes = append(result.Changes, fmt.Sprintf("set %s = %s on %q", invoiceField.Name, invoice, card.Name))
+	if options.DryRun {
+		return nil
+	}
+	path := fmt.Sprintf("cards/%s/customField/%s/item", card.ID, invoiceField.ID)
+	if err := putJSON(c.API, path, map[string]interface{}{"value": value}); err != nil {
+		return fmt.Errorf("error setting invoice on card %q: %w", card.Name, err)
+	}
+	return nil
+}
+
+// hasInvoiceComment tells whether the invoice was already commented on a card
+func (c Client) hasInvoiceComment(card *trello.Card, invoice string) (bool, error) {
+	var actions []*trello.Action
+	args := trello.Arguments{"filter": "commentCard", "limit": "1000"}
+	if err := c.API.Get(fmt.Sprintf("cards/%s/actions", card.ID), args, &actions); err != nil {
+		return false, fmt.Errorf("error fetching comments of card %q: %w", card.Name, err)
+	}
+	for _, action := range actions {
+		if action.Type == "commentCard" && action.Data != nil && strings.TrimSpace(action.Data.Text) == invoiceComment(invoice) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func invoiceComment(invoice string) string {
+	return fmt.Sprintf("Factura: %s", invoice)
+}
+
+// putJSON sends a PUT with a JSON body, which the adlio client can't do
+func putJSON(client *trello.Client, path string, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	params := url.Values{}
+	if client.Key != "" {
+		params.Set("key", client.Key)
+	}
+	if client.Token != "" {
+		params.Set("token", client.Token)
+	}
+	request, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/%s?%s", client.BaseURL, path, params.Encode()), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	httpClient := client.Client
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	client.Throttle()
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode >= http.StatusBadRequest {
+		message, _ := io.ReadAll(io.LimitReader(response.Body, 512))
+		return fmt.Errorf("PUT %s: %s: %s", path, response.Status, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
+
+func findCustomField(definitions []*trello.CustomField, name string) *trello.CustomField {
+	for _, definition := range definitions {
+		if strings.EqualFold(definition.Name, name) {
+			return definition
+		}
+	}
+	return nil
+}
+
+func findList(lists []*trello.List, name string) *trello.List {
+	for _, list := range lists {
+		if strings.EqualFold(strings.TrimSpace(list.Name), strings.TrimSpace(name)) {
+			return list
+		}
+	}
+	return nil
+}
+
+// nextMonthWord writes month in the same language, length and case as word,
+// when word is a month name
+func nextMonthWord(word string, month time.Month) (string, bool) {
+	lower := strings.ToLower(word)
+	var names []string
+	for _, candidates := range [][]string{englishMonthNames, spanishMonthNames, englishMonthAbbrs, spanishMonthAbbrs} {
+		for _, candidate := range candidates {
+			if candidate == lower {
+				names = candidates
+				break
+			}
+		}
+		if names != nil {
+			break
+		}
+	}
+	if names == nil {
+		if lower != "sept" && lower != "setiembre" {
+			return "", false
+		}
+		names = englishMonthAbbrs
+		if lower == "setiembre" {
+			names = spanishMonthNames
+		}
+	}
+
+	next := names[month-1]
+	runes := []rune(word)
+	switch {
+	case strings.ToUpper(word) == word:
+		return strings.ToUpper(next), true
+	case unicode.IsUpper(runes[0]):
+		return strings.ToUpper(next[:1]) + next[1:], true
+	default:
+		return next, true
+	}
+}
+
+// padMonth writes month with as many digits as the original
+func padMonth(month time.Month, original string) string {
+	if len(original) == 2 {
+		return fmt.Sprintf("%02d", month)
+	}
+	return strconv.Itoa(int(month))
+}
@@ -0,0 +1,145 @@
+package trello
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"txeo-tools-library/models"
+)
+
+const closeInvoice = "F-2024-10"
+
+func TestNextListName(t *testing.T) {
+	tests := []struct{ name, want string }{
+		{"Octubre 2024", "Noviembre 2024"},
+		{"Diciembre 2024", "Enero 2025"},
+		{"SEPTIEMBRE 2024", "OCTUBRE 2024"},
+		{"2024-12", "2025-01"},
+		{"12/2024", "01/2025"},
+		{"Dec '24", "Jan '25"},
+		{"Sept 2024", "Oct 2024"},
+		{"October", "November"},
+	}
+	for _, test := range tests {
+		if got, err := NextListName(test.name); err != nil || got != test.want {
+			t.Errorf("NextListName(%q) = %q, %v, want %q", test.name, got, err, test.want)
+		}
+	}
+	if _, err := NextListName("Doing"); !errors.Is(err, ErrInvalidMonth) {
+		t.Errorf("got %v for a list without month, want ErrInvalidMonth", err)
+	}
+}
+
+func TestCloseMonthSetsInvoiceField(t *testing.T) {
+	fake, client := newFakeClient(t)
+	fixtures := loadFakeFixtures(t)
+
+	// Board with a Factura text field, already set on c1
+	var fields []map[string]interface{}
+	json.Unmarshal(fixtures["boards/"+olympicsBoardID+"/customFields"], &fields)
+	fields = append(fields, map[string]interface{}{"id": "6710000000000000000000f3", "idModel": olympicsBoardID, "name": "Factura", "type": "text"})
+	fake.Set("boards/"+olympicsBoardID+"/customFields", fields)
+	var cards []map[string]interface{}
+	json.Unmarshal(fixtures["boards/"+olympicsBoardID+"/cards"], &cards)
+	items := cards[0]["customFieldItems"].([]interface{})
+	cards[0]["customFieldItems"] = append(items, map[string]interface{}{
+		"id": "6710000000000000000000d9", "idCustomField": "6710000000000000000000f3", "value": map[string]string{"text": closeInvoice},
+	})
+	fake.Set("boards/"+olympicsBoardID+"/cards", cards)
+
+	result, err := client.CloseMonth(olympicsBoardID, october, year2024, closeInvoice, CloseMonthOptions{})
+	if err != nil {
+		t.Fatalf("error closing month: %v", err)
+	}
+	if result.List != "Octubre 2024" || result.Cards != 3 || result.Stamped != 2 || result.Skipped != 1 || result.Commented != 0 {
+		t.Errorf("got %+v, want 2 cards stamped and c1 skipped", result)
+	}
+	// Noviembre 2024 is already in the board
+	if result.NextList != "" {
+		t.Errorf("created list %q, want none", result.NextList)
+	}
+	assertRequests(t, fake, []string{
+		"PUT cards/6710000000000000000000c2/customField/6710000000000000000000f3/item",
+		"PUT cards/6710000000000000000000c3/customField/6710000000000000000000f3/item",
+		"PUT lists/6710000000000000000000a2",
+	}, []string{"PUT cards/6710000000000000000000c1/customField/6710000000000000000000f3/item", "POST lists"})
+}
+
+func TestCloseMonthCommentsInvoice(t *testing.T) {
+	fake, client := newFakeClient(t)
+	comment := []map[string]interface{}{{"id": "6710000000000000000000f9", "type": "commentCard", "data": map[string]string{"text": "Factura: " + closeInvoice}}}
+	fake.Set("cards/6710000000000000000000c1/actions", []string{})
+	fake.Set("cards/6710000000000000000000c2/actions", comment)
+	fake.Set("cards/6710000000000000000000c3/actions", []string{})
+
+	// The board has no Factura field, so the invoice is commented
+	result, err := client.CloseMonth(olympicsBoardID, october, year2024, closeInvoice, CloseMonthOptions{})
+	if err != nil {
+		t.Fatalf("error closing month: %v", err)
+	}
+	if result.Commented != 2 || result.Skipped != 1 || result.Stamped != 0 {
+		t.Errorf("got %+v, want 2 cards commented and c2 skipped", result)
+	}
+	assertRequests(t, fake, []string{
+		"POST cards/6710000000000000000000c1/actions/comments",
+		"POST cards/6710000000000000000000c3/actions/comments",
+	}, []string{"POST cards/6710000000000000000000c2/actions/comments"})
+
+	// Second run, every card already has the comment
+	fake.Set("cards/6710000000000000000000c1/actions", comment)
+	fake.Set("cards/6710000000000000000000c3/actions", comment)
+	before := len(fake.Requests())
+	result, err = client.CloseMonth(olympicsBoardID, october, year2024, closeInvoice, CloseMonthOptions{Comment: true})
+	if err != nil {
+		t.Fatalf("error closing month again: %v", err)
+	}
+	if result.Commented != 0 || result.Skipped != 3 {
+		t.Errorf("second run got %+v, want every card skipped", result)
+	}
+	for _, request := range fake.Requests()[before:] {
+		if strings.HasSuffix(request, "/actions/comments") {
+			t.Errorf("second run commented again: %s", request)
+		}
+	}
+}
+
+func TestCloseMonthDryRunCreatesNothing(t *testing.T) {
+	fake, client := newFakeClient(t)
+	november := models.Months{}.GetMonths().GetMonthByName("November")
+	fake.Set("cards/6710000000000000000000c4/actions", []string{})
+	fake.Set("cards/6710000000000000000000c6/actions", []string{})
+
+	result, err := client.CloseMonth(olympicsBoardID, november, year2024, closeInvoice, CloseMonthOptions{DryRun: true, CloseList: true, ArchiveCards: true})
+	if err != nil {
+		t.Fatalf("error closing month: %v", err)
+	}
+	if result.NextList != "Diciembre 2024" || result.Commented != 2 || result.Archived != 2 || len(result.Changes) == 0 {
+		t.Errorf("got %+v, want Diciembre 2024 and 2 cards commented and archived", result)
+	}
+	for _, request := range fake.Requests() {
+		if !strings.HasPrefix(request, "GET ") {
+			t.Errorf("dry run sent %s", request)
+		}
+	}
+}
+
+// assertRequests checks the fake got every request of want and none of unwanted
+func assertRequests(t *testing.T, fake *FakeServer, want, unwanted []string) {
+	t.Helper()
+	got := map[string]bool{}
+	for _, request := range fake.Requests() {
+		got[request] = true
+	}
+	for _, request := range want {
+		if !got[request] {
+			t.Errorf("missing request %s in %q", request, fake.Requests())
+		}
+	}
+	for _, request := range unwanted {
+		if got[request] {
+			t.Errorf("unexpected request %s", request)
+		}
+	}
+}
@@ -0,0 +1,266 @@
+package trello
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"txeo-tools-library/process"
+
+	"github.com/adlio/trello"
+)
+
+// Custom field types as named by Trello
+const (
+	FieldTypeNumber   = "number"
+	FieldTypeText     = "text"
+	FieldTypeDate     = "date"
+	FieldTypeCheckbox = "checkbox"
+	FieldTypeList     = "list" // Dropdown
+)
+
+// FieldNames tells which custom fields hold the values of our conventions
+type FieldNames struct {
+	Hours      string
+	HourlyRate string
+	Client     string
+	Invoice    string
+}
+
+// DefaultFieldNames are the custom field names used in our boards
+var DefaultFieldNames = FieldNames{
+	Hours:      "Horas",
+	HourlyRate: "Tarifa",
+	Client:     "Cliente",
+	Invoice:    "Factura",
+}
+
+// FieldValue is the typed value of a custom field on a card
+type FieldValue struct {
+	Name    string
+	Type    string
+	Number  float64
+	Text    string // Text fields and the option text of dropdowns
+	Date    time.Time
+	Checked bool
+	Color   string // Color of the dropdown option
+}
+
+func (v FieldValue) String() string {
+	switch v.Type {
+	case FieldTypeNumber:
+		return strconv.FormatFloat(v.Number, 'f', -1, 64)
+	case FieldTypeDate:
+		return v.Date.Format(time.RFC3339)
+	case FieldTypeCheckbox:
+		return strconv.FormatBool(v.Checked)
+	default:
+		return v.Text
+	}
+}
+
+// CardFields are the custom field values of a card keyed by field name
+type CardFields map[string]FieldValue
+
+// DecodeCustomFields turns the raw custom field items of a card into typed
+// values using the board definitions. Items of unknown fields are skipped.
+func DecodeCustomFields(card *trello.Card, definitions []*trello.CustomField) CardFields {
+	byID := map[string]*trello.CustomField{}
+	for _, definition := range definitions {
+		byID[definition.ID] = definition
+	}
+
+	fields := CardFields{}
+	for _, item := range card.CustomFieldItems {
+		definition, ok := byID[item.IDCustomField]
+		if !ok {
+			continue
+		}
+		value := FieldValue{Name: definition.Name, Type: definition.Type}
+
+		if definition.Type == FieldTypeList {
+			option := findOption(definition, item.IDValue)
+			if option == nil {
+				continue
+			}
+			value.Text = option.Value.Text
+			value.Color = option.Color
+			fields[definition.Name] = value
+			continue
+		}
+
+		switch raw := item.Value.Get().(type) {
+		case int:
+			value.Number = float64(raw)
+		case int64:
+			value.Number = float64(raw)
+		case float64:
+			value.Number = raw
+		case string:
+			value.Text = raw
+		case bool:
+			value.Checked = raw
+		case time.Time:
+			value.Date = raw
+		default:
+			continue
+		}
+		fields[definition.Name] = value
+	}
+	return fields
+}
+
+// Strings returns the values as text by field name, as the categorization strategies expect
+func (f CardFields) Strings() map[string]string {
+	values := make(map[string]string, len(f))
+	for name, value := range f {
+		values[name] = value.String()
+	}
+	return values
+}
+
+// CategoryInput gathers the labels and custom field values of a card for the
+// categorization strategies, labels resolves the card label IDs when the card
+// came without its labels
+func CategoryInput(name string, card *trello.Card, labels map[string]*trello.Label, definitions []*trello.CustomField) process.CardCategoryInput {
+	input := process.CardCategoryInput{
+		Name:   name,
+		Labels: card.Labels,
+		Fields: DecodeCustomFields(card, definitions).Strings(),
+	}
+	if len(input.Labels) == 0 {
+		for _, labelID := range card.IDLabels {
+			if label, ok := labels[labelID]; ok {
+				input.Labels = append(input.Labels, label)
+			}
+		}
+	}
+	return input
+}
+
+// Get returns the value of a field by name, ignoring case
+func (f CardFields) Get(name string) (FieldValue, bool) {
+	if value, ok := f[name]; ok {
+		return value, true
+	}
+	for fieldName, value := range f {
+		if strings.EqualFold(fieldName, name) {
+			return value, true
+		}
+	}
+	return FieldValue{}, false
+}
+
+// Number returns a number field, accepting text fields holding a number
+func (f CardFields) Number(name string) (float64, bool) {
+	value, ok := f.Get(name)
+	if !ok {
+		return 0, false
+	}
+	if value.Type == FieldTypeNumber {
+		return value.Number, true
+	}
+	number, err := strconv.ParseFloat(strings.Replace(strings.TrimSpace(value.Text), ",", ".", 1), 64)
+	return number, err == nil
+}
+
+// Text returns the text of a text or dropdown field
+func (f CardFields) Text(name string) (string, bool) {
+	value, ok := f.Get(name)
+	if !ok || (value.Type != FieldTypeText && value.Type != FieldTypeList) {
+		return "", false
+	}
+	return value.Text, true
+}
+
+// Date returns a date field
+func (f CardFields) Date(name string) (time.Time, bool) {
+	value, ok := f.Get(name)
+	if !ok || value.Type != FieldTypeDate {
+		return time.Time{}, false
+	}
+	return value.Date, true
+}
+
+// Checked returns a checkbox field, false when missing
+func (f CardFields) Checked(name string) bool {
+	value, ok := f.Get(name)
+	return ok && value.Type == FieldTypeCheckbox && value.Checked
+}
+
+// Hours returns the hours of the card from a number field or a text field
+// written like the task names ("1h30", "45m", "1,5")
+func (f CardFields) Hours(names FieldNames) (float64, bool) {
+	if hours, ok := f.Number(names.Hours); ok {
+		return hours, true
+	}
+	text, ok := f.Text(names.Hours)
+	if !ok {
+		return 0, false
+	}
+	_, hours, found, _ := process.ParseDuration(text)
+	return hours, found
+}
+
+// HourlyRate returns the hourly rate of the card
+func (f CardFields) HourlyRate(names FieldNames) (float64, bool) {
+	return f.Number(names.HourlyRate)
+}
+
+// Client returns the client of the card
+func (f CardFields) Client(names FieldNames) (string, bool) {
+	return f.Text(names.Client)
+}
+
+// FieldDecoder decodes the custom fields of cards, fetching the definitions
+// of each board only once
+type FieldDecoder struct {
+	src         Source
+	mu          sync.Mutex
+	definitions map[string][]*trello.CustomField
+}
+
+func NewFieldDecoder(src Source) *FieldDecoder {
+	return &FieldDecoder{src: src, definitions: map[string][]*trello.CustomField{}}
+}
+
+// Definitions returns the custom field definitions of a board
+func (d *FieldDecoder) Definitions(boardID string) ([]*trello.CustomField, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if definitions, ok := d.definitions[boardID]; ok {
+		return definitions, nil
+	}
+	definitions, err := d.src.GetCustomFields(boardID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching custom fields of board %s: %w", boardID, err)
+	}
+	d.definitions[boardID] = definitions
+	return definitions, nil
+}
+
+// Decode returns the typed custom fields of a card
+func (d *FieldDecoder) Decode(card *trello.Card) (CardFields, error) {
+	if len(card.CustomFieldItems) == 0 {
+		return CardFields{}, nil
+	}
+	definitions, err := d.Definitions(card.IDBoard)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeCustomFields(card, definitions), nil
+}
+
+/* ╭──────────────────────────────────────────╮ */
+/* │              AUX FUNCTIONS               │ */
+/* ╰──────────────────────────────────────────╯ */
+func findOption(definition *trello.CustomField, optionID string) *trello.CustomFieldOption {
+	for _, option := range definition.Options {
+		if option.ID == optionID {
+			return option
+		}
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package trello
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/adlio/trello"
+)
+
+const customFieldsJSON = `[
+	{"id": "f1", "name": "Horas", "type": "number"},
+	{"id": "f2", "name": "Cliente", "type": "list", "options": [
+		{"id": "e1", "value": {"text": "IOC"}, "color": "blue"},
+		{"id": "e2", "value": {"text": "LIV Golf"}, "color": "green"}
+	]},
+	{"id": "f3", "name": "Tarifa", "type": "text"},
+	{"id": "f4", "name": "Entrega", "type": "date"},
+	{"id": "f5", "name": "Facturado", "type": "checkbox"},
+	{"id": "f6", "name": "Estimación", "type": "text"}
+]`
+
+func decodeTestCard(t *testing.T, items string) (*trello.Card, []*trello.CustomField) {
+	t.Helper()
+	var definitions []*trello.CustomField
+	if err := json.Unmarshal([]byte(customFieldsJSON), &definitions); err != nil {
+		t.Fatalf("error decoding definitions: %v", err)
+	}
+	card := &trello.Card{}
+	if err := json.Unmarshal([]byte(`{"customFieldItems": `+items+`}`), card); err != nil {
+		t.Fatalf("error decoding card: %v", err)
+	}
+	return card, definitions
+}
+
+func TestDecodeCustomFields(t *testing.T) {
+	card, definitions := decodeTestCard(t, `[
+		{"idCustomField": "f1", "value": {"number": "1.5"}},
+		{"idCustomField": "f2", "idValue": "e2"},
+		{"idCustomField": "f3", "value": {"text": "60,5"}},
+		{"idCustomField": "f4", "value": {"date": "2024-10-25T17:00:00.000Z"}},
+		{"idCustomField": "f5", "value": {"checked": "true"}},
+		{"idCustomField": "f6", "value": {"text": "1h30"}},
+		{"idCustomField": "f9", "value": {"text": "unknown field"}}
+	]`)
+	fields := DecodeCustomFields(card, definitions)
+
+	if len(fields) != 6 {
+		t.Errorf("got %d fields, want 6 without the unknown one: %+v", len(fields), fields)
+	}
+	if hours, ok := fields.Number("horas"); !ok || hours != 1.5 {
+		t.Errorf("Number(horas) = %v, %v", hours, ok)
+	}
+	if rate, ok := fields.HourlyRate(DefaultFieldNames); !ok || rate != 60.5 {
+		t.Errorf("HourlyRate = %v, %v, want the text field read as a number", rate, ok)
+	}
+	if client, ok := fields.Client(DefaultFieldNames); !ok || client != "LIV Golf" || fields["Cliente"].Color != "green" {
+		t.Errorf("Client = %q, %v with color %q", client, ok, fields["Cliente"].Color)
+	}
+	if due, ok := fields.Date("Entrega"); !ok || !due.Equal(time.Date(2024, 10, 25, 17, 0, 0, 0, time.UTC)) {
+		t.Errorf("Date(Entrega) = %v, %v", due, ok)
+	}
+	if !fields.Checked("Facturado") || fields.Checked("Horas") || fields.Checked("Missing") {
+		t.Error("Checked should only be true for the checked checkbox")
+	}
+	if _, ok := fields.Text("Horas"); ok {
+		t.Error("Text(Horas) of a number field should not be found")
+	}
+	if hours, ok := fields.Hours(FieldNames{Hours: "Estimación"}); !ok || hours != 1.5 {
+		t.Errorf("Hours from a text field = %v, %v, want 1.5", hours, ok)
+	}
+
+	want := map[string]string{
+		"Horas": "1.5", "Cliente": "LIV Golf", "Tarifa": "60,5", "Entrega": "2024-10-25T17:00:00Z", "Facturado": "true", "Estimación": "1h30",
+	}
+	got := fields.Strings()
+	for name, value := range want {
+		if got[name] != value {
+			t.Errorf("Strings()[%s] = %q, want %q", name, got[name], value)
+		}
+	}
+}
+
+func TestDecodeCustomFieldsSkipsUnknownOptions(t *testing.T) {
+	card, definitions := decodeTestCard(t, `[
+		{"idCustomField": "f1", "value": {"number": "2"}},
+		{"idCustomField": "f2", "idValue": "e9"}
+	]`)
+	fields := DecodeCustomFields(card, definitions)
+	if _, ok := fields.Client(DefaultFieldNames); ok {
+		t.Error("got a client from an unknown dropdown option")
+	}
+	if hours, ok := fields.Hours(DefaultFieldNames); !ok || hours != 2 {
+		t.Errorf("Hours = %v, %v, want the integer number", hours, ok)
+	}
+}
+
+func TestFieldDecoder(t *testing.T) {
+	fake, client := newFakeClient(t)
+	cards, err := client.GetBoardCards(olympicsBoardID)
+	if err != nil {
+		t.Fatalf("error fetching cards: %v", err)
+	}
+
+	decoder := NewFieldDecoder(NewAPISource(client.API))
+	for _, card := range cards {
+		fields, err := decoder.Decode(card)
+		if err != nil {
+			t.Fatalf("error decoding %s: %v", card.Name, err)
+		}
+		if card.ID != "6710000000000000000000c1" {
+			if len(fields) != 0 {
+				t.Errorf("%s: got fields %+v", card.Name, fields)
+			}
+			continue
+		}
+		if hours, _ := fields.Hours(DefaultFieldNames); hours != 1.5 {
+			t.Errorf("%s: got %vh, want 1.5", card.Name, hours)
+		}
+		if client, _ := fields.Client(DefaultFieldNames); client != "IOC" {
+			t.Errorf("%s: got client %q, want IOC", card.Name, client)
+		}
+	}
+	decoder.Definitions(olympicsBoardID)
+	if got := countRequests(fake, "GET boards/"+olympicsBoardID+"/customFields"); got != 1 {
+		t.Errorf("custom fields fetched %d times, want once", got)
+	}
+}
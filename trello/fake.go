@@ -0,0 +1,347 @@
+package trello
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Fixtures are Trello responses keyed by request path without the API version,
+// e.g. "members/me", "boards/<id>/lists" or "lists/<id>/cards". Responses to
+// writes are keyed by method and path, e.g. "PUT cards/<id>".
+type Fixtures map[string]json.RawMessage
+
+// RedactedValue replaces secrets in recorded fixtures
+const RedactedValue = "REDACTED"
+
+// Fields blanked in recorded responses
+var redactedFields = map[string]bool{"email": true, "token": true, "key": true, "secret": true, "idMemberReferrer": true}
+
+// LoadFixtures reads a fixtures file, or every *.json fixtures file of a directory
+func LoadFixtures(path string) (Fixtures, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	files := []string{path}
+	if info.IsDir() {
+		if files, err = filepath.Glob(filepath.Join(path, "*.json")); err != nil {
+			return nil, err
+		}
+	}
+
+	fixtures := Fixtures{}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		var fileFixtures Fixtures
+		if err := json.Unmarshal(data, &fileFixtures); err != nil {
+			return nil, fmt.Errorf("error parsing fixtures %s: %w", file, err)
+		}
+		for path, response := range fileFixtures {
+			fixtures[path] = response
+		}
+	}
+	return fixtures, nil
+}
+
+// Save writes the fixtures as indented JSON sorted by path
+func (f Fixtures) Save(path string) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// FakeServer is an in-process Trello API serving fixtures, for tests and demos
+// without credentials or network. GET requests are answered from the fixtures
+// (the cards of a list are derived from the board cards when not seeded), and
+// writes are accepted and recorded so they can be asserted.
+type FakeServer struct {
+	*httptest.Server
+	Key   string // Expected app key, any when empty
+	Token string // Expected token, any when empty
+
+	mu       sync.Mutex
+	fixtures Fixtures
+	requests []string
+	lastID   int
+}
+
+// NewFakeServer starts a fake Trello API with the given fixtures
+func NewFakeServer(fixtures Fixtures) *FakeServer {
+	fake := &FakeServer{fixtures: Fixtures{}}
+	for path, response := range fixtures {
+		fake.fixtures[path] = response
+	}
+	fake.Server = httptest.NewServer(http.HandlerFunc(fake.serveHTTP))
+	return fake
+}
+
+// Config returns a client config pointing to the fake server
+func (f *FakeServer) Config() Config {
+	key, token := f.Key, f.Token
+	if key == "" {
+		key = "fake-key"
+	}
+	if token == "" {
+		token = "fake-token"
+	}
+	return Config{AppKey: key, Token: token, BaseURL: f.URL, HTTPClient: f.Client()}
+}
+
+// Set adds or replaces the response of a path
+func (f *FakeServer) Set(path string, response interface{}) error {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fixtures[strings.Trim(path, "/")] = data
+	return nil
+}
+
+// Requests returns the requests received as "METHOD path"
+func (f *FakeServer) Requests() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string{}, f.requests...)
+}
+
+func (f *FakeServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	path := fixturePath(r.URL.Path, "/1")
+
+	f.mu.Lock()
+	f.requests = append(f.requests, r.Method+" "+path)
+	f.mu.Unlock()
+
+	query := r.URL.Query()
+	if (f.Key != "" && query.Get("key") != f.Key) || (f.Token != "" && query.Get("token") != f.Token) {
+		http.Error(w, "invalid key", http.StatusUnauthorized)
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(f.writeResponse(r.Method, path, query))
+		return
+	}
+
+	response, ok := f.lookup(path)
+	if !ok {
+		http.Error(w, "The requested resource was not found.", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(response)
+}
+
+// writeResponse answers POST, PUT and DELETE requests. A fixture set for
+// "METHOD path" wins, otherwise the arguments are echoed back with a new ID.
+// Positions are left out as Trello answers them as numbers.
+func (f *FakeServer) writeResponse(method, path string, query url.Values) []byte {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if response, ok := f.fixtures[method+" "+path]; ok {
+		return response
+	}
+	if strings.HasSuffix(path, "/idLabels") {
+		data, _ := json.Marshal([]string{query.Get("value")})
+		return data
+	}
+	echo := map[string]interface{}{}
+	for name := range query {
+		switch value := query.Get(name); {
+		case name == "key" || name == "token" || name == "pos":
+		case value == "true" || value == "false":
+			echo[name] = value == "true"
+		default:
+			echo[name] = value
+		}
+	}
+	if method == http.MethodPost {
+		f.lastID++
+		echo["id"] = fmt.Sprintf("%024x", 0xfa4e0000+f.lastID)
+	}
+	data, _ := json.Marshal(echo)
+	return data
+}
+
+func (f *FakeServer) lookup(path string) (json.RawMessage, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if response, ok := f.fixtures[path]; ok {
+		return response, true
+	}
+
+	// lists/<id>/cards --> cards of boards/<board>/cards in that list
+	parts := strings.Split(path, "/")
+	if len(parts) == 3 && parts[0] == "lists" && parts[2] == "cards" {
+		return f.listCards(parts[1])
+	}
+	// members/<username> --> members/me
+	if len(parts) == 2 && parts[0] == "members" {
+		response, ok := f.fixtures["members/me"]
+		return response, ok
+	}
+	// members/<username>/boards --> members/me/boards
+	if len(parts) == 3 && parts[0] == "members" && parts[2] == "boards" {
+		response, ok := f.fixtures["members/me/boards"]
+		return response, ok
+	}
+	return nil, false
+}
+
+func (f *FakeServer) listCards(listID string) (json.RawMessage, bool) {
+	var paths []string
+	for path := range f.fixtures {
+		if strings.HasPrefix(path, "boards/") && strings.HasSuffix(path, "/cards") {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	var cards []json.RawMessage
+	found := false
+	for _, path := range paths {
+		var boardCards []json.RawMessage
+		if err := json.Unmarshal(f.fixtures[path], &boardCards); err != nil {
+			continue
+		}
+		for _, card := range boardCards {
+			var ids struct {
+				IDList string `json:"idList"`
+			}
+			json.Unmarshal(card, &ids)
+			if ids.IDList == listID {
+				cards = append(cards, card)
+			}
+		}
+		found = true
+	}
+	if !found {
+		return nil, false
+	}
+	if cards == nil {
+		cards = []json.RawMessage{}
+	}
+	data, _ := json.Marshal(cards)
+	return data, true
+}
+
+// Recorder is an http.RoundTripper that captures real Trello GET responses as
+// fixtures, stripping the credentials and personal fields.
+type Recorder struct {
+	Base     http.RoundTripper // http.DefaultTransport when nil
+	BasePath string            // Path prefix of the API, "/1" when empty
+	Secrets  []string          // Values replaced by RedactedValue, the key and token at least
+
+	mu       sync.Mutex
+	fixtures Fixtures
+}
+
+// NewRecorder records the responses going through base, redacting key and token
+func NewRecorder(base http.RoundTripper, key, token string) *Recorder {
+	return &Recorder{Base: base, Secrets: []string{key, token}, fixtures: Fixtures{}}
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := r.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(req)
+	if err != nil || req.Method != http.MethodGet || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	basePath := r.BasePath
+	if basePath == "" {
+		basePath = "/1"
+	}
+	if redacted, err := r.redact(body); err == nil {
+		r.mu.Lock()
+		if r.fixtures == nil {
+			r.fixtures = Fixtures{}
+		}
+		r.fixtures[fixturePath(req.URL.Path, basePath)] = redacted
+		r.mu.Unlock()
+	}
+	return resp, nil
+}
+
+// Fixtures returns a copy of everything recorded so far
+func (r *Recorder) Fixtures() Fixtures {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fixtures := Fixtures{}
+	for path, response := range r.fixtures {
+		fixtures[path] = response
+	}
+	return fixtures
+}
+
+// Save writes the recorded fixtures to path
+func (r *Recorder) Save(path string) error {
+	return r.Fixtures().Save(path)
+}
+
+func (r *Recorder) redact(body []byte) (json.RawMessage, error) {
+	text := string(body)
+	for _, secret := range r.Secrets {
+		if secret != "" {
+			text = strings.ReplaceAll(text, secret, RedactedValue)
+		}
+	}
+	var value interface{}
+	if err := json.Unmarshal([]byte(text), &value); err != nil {
+		return nil, err
+	}
+	return json.Marshal(redactFields(value))
+}
+
+func redactFields(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for field, fieldValue := range v {
+			if redactedFields[field] && fieldValue != nil {
+				v[field] = RedactedValue
+				continue
+			}
+			v[field] = redactFields(fieldValue)
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = redactFields(v[i])
+		}
+	}
+	return value
+}
+
+func fixturePath(urlPath, basePath string) string {
+	return strings.Trim(strings.TrimPrefix(urlPath, basePath+"/"), "/")
+}
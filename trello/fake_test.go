@@ -0,0 +1,140 @@
+package trello
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"txeo-tools-library/models"
+)
+
+const olympicsBoardID = "617c56690fcb27430e740522"
+
+var (
+	october  = models.Months{}.GetMonths().GetMonthByName("October")
+	year2024 = models.Year{}.GetYears()[0]
+)
+
+func loadFakeFixtures(t *testing.T) Fixtures {
+	t.Helper()
+	fixtures, err := LoadFixtures(filepath.Join("testdata", "fake"))
+	if err != nil {
+		t.Fatalf("error loading fixtures: %v", err)
+	}
+	return fixtures
+}
+
+func newFakeClient(t *testing.T) (*FakeServer, Client) {
+	t.Helper()
+	fake := NewFakeServer(loadFakeFixtures(t))
+	t.Cleanup(fake.Close)
+
+	config := fake.Config()
+	config.Boards.CachePath = filepath.Join(t.TempDir(), "boards.json")
+	client, err := New(config)
+	if err != nil {
+		t.Fatalf("error creating client: %v", err)
+	}
+	return fake, client
+}
+
+func TestFakeServerServesMonthLists(t *testing.T) {
+	_, client := newFakeClient(t)
+
+	board, err := client.Board("olympics")
+	if err != nil {
+		t.Fatalf("error fetching board: %v", err)
+	}
+	if board.Name != "Olympics" {
+		t.Errorf("board name = %q, want Olympics", board.Name)
+	}
+
+	month := models.Months{}.GetMonths().GetMonthByName("October")
+	year := models.Year{}.GetYears()[0]
+	monthLists, err := FetchMonthLists(client, board.ID, month, year)
+	if err != nil {
+		t.Fatalf("error fetching month lists: %v", err)
+	}
+	if len(monthLists) != 1 || monthLists[0].List.Name != "Octubre 2024" {
+		t.Fatalf("month lists = %+v, want only Octubre 2024", monthLists)
+	}
+	if got := len(monthLists[0].Cards); got != 3 {
+		t.Errorf("cards = %d, want 3", got)
+	}
+}
+
+func TestBoardRediscoverySavesConfiguredCache(t *testing.T) {
+	fake, client := newFakeClient(t)
+	cachePath := client.Boards.CachePath()
+	if err := os.Remove(cachePath); err != nil {
+		t.Fatalf("error removing the boards cache: %v", err)
+	}
+	fake.Set("members/me/boards", []map[string]string{
+		{"id": "617c56690fcb27430e740522", "name": "Olympics"},
+		{"id": "66462e4bca554f21f82b8e4a", "name": "LivGolf"},
+	})
+
+	if _, err := client.Board("LivGolf"); err == nil {
+		t.Fatal("expected an error fetching a board without fixtures")
+	}
+	registry := NewBoardRegistry()
+	if err := registry.LoadCache(cachePath); err != nil {
+		t.Fatalf("error loading the cache saved by the rediscovery: %v", err)
+	}
+	if names := registry.Names(); len(names) != 2 || names[0] != "LivGolf" {
+		t.Errorf("cached boards = %v, want LivGolf and Olympics", names)
+	}
+}
+
+func TestFakeServerRejectsWrongCredentials(t *testing.T) {
+	fake := NewFakeServer(Fixtures{})
+	defer fake.Close()
+	fake.Key, fake.Token = "right-key", "right-token"
+
+	config := fake.Config()
+	config.Token = "wrong-token"
+	if _, err := New(config); err == nil {
+		t.Fatal("expected an error with a wrong token")
+	}
+}
+
+func TestRecorderRedactsSecrets(t *testing.T) {
+	fake := NewFakeServer(Fixtures{})
+	defer fake.Close()
+	fake.Set("members/me", map[string]string{
+		"id":         "5a1b2c3d4e5f60718293a4b5",
+		"email":      "txeo@example.com",
+		"avatarHash": "fake-token",
+	})
+	fake.Set("members/me/boards", []map[string]string{{"id": "617c56690fcb27430e740522", "name": "Olympics"}})
+
+	recorder := NewRecorder(http.DefaultTransport, "fake-key", "fake-token")
+	config := fake.Config()
+	config.HTTPClient = &http.Client{Transport: recorder}
+	config.Boards.CachePath = filepath.Join(t.TempDir(), "boards.json")
+	if _, err := New(config); err != nil {
+		t.Fatalf("error creating client: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "recorded.json")
+	if err := recorder.Save(path); err != nil {
+		t.Fatalf("error saving fixtures: %v", err)
+	}
+	fixtures, err := LoadFixtures(path)
+	if err != nil {
+		t.Fatalf("error loading recorded fixtures: %v", err)
+	}
+
+	var member map[string]string
+	if err := json.Unmarshal(fixtures["members/me"], &member); err != nil {
+		t.Fatalf("members/me not recorded: %v", err)
+	}
+	if member["email"] != RedactedValue || member["avatarHash"] != RedactedValue {
+		t.Errorf("secrets not redacted: %v", member)
+	}
+	if _, ok := fixtures["members/5a1b2c3d4e5f60718293a4b5/boards"]; !ok {
+		t.Errorf("member boards not recorded, got %v", fixtures)
+	}
+}
@@ -0,0 +1,201 @@
+package trello
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"txeo-tools-library/models"
+
+	"github.com/adlio/trello"
+)
+
+// DefaultFetchWorkers is the number of board/month pairs fetched at the same time
+const DefaultFetchWorkers = 4
+
+// ContextSource is a Source whose requests can be bound to a context
+type ContextSource interface {
+	Source
+	WithContext(ctx context.Context) Source
+}
+
+// FetchKey identifies the result of a board and month
+type FetchKey struct {
+	BoardID string
+	Month   string // Month name, as in models.Month
+}
+
+// FetchResults are the month lists of every board and month fetched
+type FetchResults map[FetchKey][]MonthList
+
+// FetchOptions tunes FetchBoardsMonths
+type FetchOptions struct {
+	Workers     int  // DefaultFetchWorkers when zero
+	SkipMissing bool // Don't report boards without a list for a month as errors
+}
+
+// FetchErrors holds the errors of every board that failed, keyed by board ID
+type FetchErrors map[string][]error
+
+func (e FetchErrors) Error() string {
+	var boards []string
+	for boardID := range e {
+		boards = append(boards, boardID)
+	}
+	sort.Strings(boards)
+
+	var messages []string
+	for _, boardID := range boards {
+		for _, err := range e[boardID] {
+			messages = append(messages, fmt.Sprintf("board %s: %v", boardID, err))
+		}
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (e FetchErrors) Unwrap() []error {
+	var errs []error
+	for _, boardErrors := range e {
+		errs = append(errs, boardErrors...)
+	}
+	return errs
+}
+
+// FetchBoardsMonths fetches the month lists and cards of every board and month
+// concurrently with a bounded pool of workers; the lists of each board are
+// fetched once, by the first of its months. A failing board doesn't stop the
+// others: results hold everything that was fetched and the error, a FetchErrors,
+// lists what failed. Cancelling ctx stops pending work and returns ctx.Err().
+func FetchBoardsMonths(ctx context.Context, src Source, boardIDs []string, months models.Months, year models.Year, options FetchOptions) (FetchResults, error) {
+	if options.Workers <= 0 {
+		options.Workers = DefaultFetchWorkers
+	}
+	if contextSource, ok := src.(ContextSource); ok {
+		src = contextSource.WithContext(ctx)
+	}
+
+	type job struct {
+		boardID string
+		month   models.Month
+	}
+	jobs := make(chan job)
+	lists := map[string]*boardLists{}
+	for _, boardID := range boardIDs {
+		lists[boardID] = &boardLists{}
+	}
+	results := FetchResults{}
+	fetchErrors := FetchErrors{}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for i := 0; i < options.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				if ctx.Err() != nil {
+					continue
+				}
+				boardLists, first, listsErr := lists[job.boardID].get(src, job.boardID)
+				var monthLists []MonthList
+				var err error
+				if listsErr == nil {
+					monthLists, err = fetchMonthListsCards(src, boardLists, job.month, year)
+				}
+
+				mu.Lock()
+				switch {
+				case listsErr != nil:
+					// Reported once for the board, not for each of its months
+					if first && ctx.Err() == nil {
+						fetchErrors[job.boardID] = append(fetchErrors[job.boardID], listsErr)
+					}
+				case err == nil:
+					results[FetchKey{BoardID: job.boardID, Month: job.month.Name}] = monthLists
+				case options.SkipMissing && errors.Is(err, ErrMonthListNotFound):
+				case ctx.Err() == nil:
+					fetchErrors[job.boardID] = append(fetchErrors[job.boardID], fmt.Errorf("%s: %w", job.month.Name, err))
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+send:
+	for _, boardID := range boardIDs {
+		for _, month := range months {
+			select {
+			case jobs <- job{boardID: boardID, month: month}:
+			case <-ctx.Done():
+				break send
+			}
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return results, ctx.Err()
+	}
+	if len(fetchErrors) > 0 {
+		return results, fetchErrors
+	}
+	return results, nil
+}
+
+// Months returns the month names fetched for a board
+func (r FetchResults) Months(boardID string) []string {
+	var months []string
+	for key := range r {
+		if key.BoardID == boardID {
+			months = append(months, key.Month)
+		}
+	}
+	sort.Strings(months)
+	return months
+}
+
+// WithContext binds the requests of the API source to ctx
+func (s apiSource) WithContext(ctx context.Context) Source {
+	return apiSource{client: s.client.WithContext(ctx)}
+}
+
+// WithContext binds the requests of the client to ctx
+func (c Client) WithContext(ctx context.Context) Source {
+	c.API = c.API.WithContext(ctx)
+	return c
+}
+
+// WithContext binds the requests made on cache misses to ctx
+func (c *Cache) WithContext(ctx context.Context) Source {
+	copied := *c
+	if c.api != nil {
+		copied.api = c.api.WithContext(ctx)
+	}
+	return &copied
+}
+
+/* ╭──────────────────────────────────────────╮ */
+/* │              AUX FUNCTIONS               │ */
+/* ╰──────────────────────────────────────────╯ */
+// boardLists fetches the lists of a board once for all its months
+type boardLists struct {
+	once  sync.Once
+	lists []*trello.List
+	err   error
+}
+
+// get returns the lists of the board, first is true only for the call that fetched them
+func (b *boardLists) get(src Source, boardID string) (lists []*trello.List, first bool, err error) {
+	b.once.Do(func() {
+		first = true
+		b.lists, b.err = src.GetLists(boardID)
+		if b.err != nil {
+			b.err = fmt.Errorf("error fetching lists of board %s: %w", boardID, b.err)
+		}
+	})
+	return b.lists, first, b.err
+}
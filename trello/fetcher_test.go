@@ -0,0 +1,78 @@
+package trello
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"txeo-tools-library/models"
+)
+
+const missingBoardID = "617c56690fcb27430e740599"
+
+func TestFetchBoardsMonths(t *testing.T) {
+	months := models.Months{}.GetMonths()
+	wanted := models.Months{months.GetMonthByName("October"), months.GetMonthByName("November"), months.GetMonthByName("December")}
+
+	for _, workers := range []int{1, 3, 8} {
+		fake, client := newFakeClient(t)
+		results, err := FetchBoardsMonths(context.Background(), NewAPISource(client.API), []string{olympicsBoardID, missingBoardID}, wanted, year2024, FetchOptions{Workers: workers, SkipMissing: true})
+
+		var fetchErrors FetchErrors
+		if !errors.As(err, &fetchErrors) || len(fetchErrors) != 1 || len(fetchErrors[missingBoardID]) != 1 {
+			t.Fatalf("%d workers: got error %v, want one for the missing board", workers, err)
+		}
+		if !strings.HasPrefix(err.Error(), "board "+missingBoardID+": error fetching lists") {
+			t.Errorf("%d workers: error = %q", workers, err)
+		}
+		if got := results.Months(olympicsBoardID); !reflect.DeepEqual(got, []string{"November", "October"}) {
+			t.Errorf("%d workers: got months %q, want November and October without December", workers, got)
+		}
+		for month, want := range map[string]struct {
+			list  string
+			cards int
+		}{"October": {"Octubre 2024", 3}, "November": {"Noviembre 2024", 2}} {
+			monthLists := results[FetchKey{BoardID: olympicsBoardID, Month: month}]
+			if len(monthLists) != 1 || monthLists[0].List.Name != want.list || len(monthLists[0].Cards) != want.cards {
+				t.Errorf("%d workers: %s = %+v, want %s with %d cards", workers, month, monthLists, want.list, want.cards)
+			}
+		}
+
+		// The lists of each board once, whatever the number of months
+		for _, boardID := range []string{olympicsBoardID, missingBoardID} {
+			if got := countRequests(fake, "GET boards/"+boardID+"/lists"); got != 1 {
+				t.Errorf("%d workers: lists of board %s fetched %d times, want once", workers, boardID, got)
+			}
+		}
+	}
+}
+
+func TestFetchBoardsMonthsMissingMonth(t *testing.T) {
+	_, client := newFakeClient(t)
+	december := models.Months{}.GetMonths().GetMonthByName("December")
+
+	results, err := FetchBoardsMonths(context.Background(), NewAPISource(client.API), []string{olympicsBoardID}, models.Months{october, december}, year2024, FetchOptions{})
+	if !errors.Is(err, ErrMonthListNotFound) || !strings.Contains(err.Error(), "December") {
+		t.Errorf("got error %v, want December not found", err)
+	}
+	if got := results.Months(olympicsBoardID); !reflect.DeepEqual(got, []string{"October"}) {
+		t.Errorf("got months %q, want October fetched anyway", got)
+	}
+}
+
+func TestFetchBoardsMonthsCancelled(t *testing.T) {
+	fake, client := newFakeClient(t)
+	sent := len(fake.Requests())
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results, err := FetchBoardsMonths(ctx, NewAPISource(client.API), []string{olympicsBoardID}, models.Months{october}, year2024, FetchOptions{})
+	if !errors.Is(err, context.Canceled) || len(results) != 0 {
+		t.Errorf("got %d results and error %v, want nothing and context.Canceled", len(results), err)
+	}
+	if requests := fake.Requests()[sent:]; len(requests) != 0 {
+		t.Errorf("cancelled fetch sent %q", requests)
+	}
+}
@@ -0,0 +1,236 @@
+package trello
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"txeo-tools-library/models"
+	"txeo-tools-library/process"
+
+	"github.com/adlio/trello"
+)
+
+// Label changes reported by SyncCategoryLabels
+const (
+	LabelCreate = "create"
+	LabelAdd    = "add"
+	LabelRemove = "remove"
+	LabelKeep   = "keep" // The card has another category label and Force is off
+)
+
+// trelloLabelColors are the colors accepted by Trello for a label
+var trelloLabelColors = map[string]bool{
+	"green": true, "yellow": true, "orange": true, "red": true, "purple": true,
+	"blue": true, "sky": true, "lime": true, "pink": true, "black": true,
+}
+
+// LabelOptions tunes SyncCategoryLabels
+type LabelOptions struct {
+	DryRun      bool                     // Only report the changes
+	Force       bool                     // Replace category labels set by hand with the computed one
+	RemoveStale bool                     // Remove category labels that don't match the computed category
+	Registry    *models.CategoryRegistry // process.CategoryRegistry() when nil
+}
+
+// LabelChange is a change made (or to make, on dry runs) on a board
+type LabelChange struct {
+	Action   string
+	CardID   string // Empty when creating a label
+	CardName string
+	Label    string
+	Category string
+}
+
+func (c LabelChange) String() string {
+	if c.CardID == "" {
+		return fmt.Sprintf("%s label %q", c.Action, c.Label)
+	}
+	return fmt.Sprintf("%s label %q on %q", c.Action, c.Label, c.CardName)
+}
+
+// LabelSync summarizes a SyncCategoryLabels run
+type LabelSync struct {
+	DryRun  bool
+	Cards   int
+	Changes []LabelChange
+}
+
+// CategoryLabelName is the name of the label of a category, its icon followed by its name
+func CategoryLabelName(category models.Category) string {
+	if category.Icon == "" {
+		return category.Name
+	}
+	return category.Icon + " " + category.Name
+}
+
+// SyncCategoryLabels makes sure the board has one label per category and
+// applies to every card of the month the label of the category computed by
+// process.GetTaskCategory. Cards already labeled with another category are
+// left alone unless options.Force is set.
+func (c Client) SyncCategoryLabels(boardID string, month models.Month, year models.Year, options LabelOptions) (LabelSync, error) {
+	result := LabelSync{DryRun: options.DryRun}
+	registry := options.Registry
+	if registry == nil {
+		registry = process.CategoryRegistry()
+	}
+
+	src := NewAPISource(c.API)
+	monthLists, err := FetchMonthLists(src, boardID, month, year)
+	if err != nil {
+		return result, err
+	}
+	boardLabels, err := src.GetLabels(boardID)
+	if err != nil {
+		return result, fmt.Errorf("error fetching labels of board %s: %w", boardID, err)
+	}
+
+	// Category name -> label, creating the missing ones
+	labels := map[string]*trello.Label{}
+	for _, category := range categoryLabels(registry) {
+		name := CategoryLabelName(category)
+		label := findLabel(boardLabels, name)
+		if label == nil {
+			label = &trello.Label{Name: name, Color: labelColor(category.Color)}
+			result.Changes = append(result.Changes, LabelChange{Action: LabelCreate, Label: name, Category: category.Name})
+			if !options.DryRun {
+				board := &trello.Board{ID: boardID}
+				board.SetClient(c.API)
+				if err := board.CreateLabel(label); err != nil {
+					return result, fmt.Errorf("error creating label %q: %w", name, err)
+				}
+			}
+		}
+		labels[category.Name] = label
+	}
+
+	for _, monthList := range monthLists {
+		for _, card := range monthList.Cards {
+			result.Cards++
+			card.SetClient(c.API)
+			changes, err := syncCardLabels(card, labels, options)
+			result.Changes = append(result.Changes, changes...)
+			if err != nil {
+				return result, err
+			}
+		}
+	}
+	return result, nil
+}
+
+/* ╭──────────────────────────────────────────╮ */
+/* │              AUX FUNCTIONS               │ */
+/* ╰──────────────────────────────────────────╯ */
+func syncCardLabels(card *trello.Card, labels map[string]*trello.Label, options LabelOptions) ([]LabelChange, error) {
+	name, _, _, _ := process.ParseDuration(card.Name)
+	category := process.GetTaskCategory(name)
+	wanted, ok := labels[category]
+	if !ok {
+		return nil, nil
+	}
+
+	// Category labels already on the card, by category name so the changes
+	// are always reported in the same order
+	categories := make([]string, 0, len(labels))
+	for labelCategory := range labels {
+		categories = append(categories, labelCategory)
+	}
+	sort.Strings(categories)
+	hasWanted := false
+	var others []*trello.Label
+	for _, labelCategory := range categories {
+		label := labels[labelCategory]
+		if !cardHasLabel(card, label) {
+			continue
+		}
+		if labelCategory == category {
+			hasWanted = true
+		} else {
+			others = append(others, label)
+		}
+	}
+
+	var changes []LabelChange
+	change := func(action string, label *trello.Label) LabelChange {
+		return LabelChange{Action: action, CardID: card.ID, CardName: card.Name, Label: label.Name, Category: category}
+	}
+
+	if len(others) > 0 && !hasWanted && !options.Force {
+		for _, label := range others {
+			changes = append(changes, change(LabelKeep, label))
+		}
+		return changes, nil
+	}
+
+	if !hasWanted {
+		changes = append(changes, change(LabelAdd, wanted))
+		if !options.DryRun {
+			if err := card.AddIDLabel(wanted.ID); err != nil {
+				return changes, fmt.Errorf("error adding label %q to card %q: %w", wanted.Name, card.Name, err)
+			}
+		}
+	}
+
+	if !options.RemoveStale && !options.Force {
+		return changes, nil
+	}
+	for _, label := range others {
+		changes = append(changes, change(LabelRemove, label))
+		if options.DryRun {
+			continue
+		}
+		if err := card.RemoveIDLabel(label.ID, &trello.Label{}); err != nil {
+			return changes, fmt.Errorf("error removing label %q from card %q: %w", label.Name, card.Name, err)
+		}
+	}
+	return changes, nil
+}
+
+// categoryLabels returns one category per name, skipping subcategory rows
+func categoryLabels(registry *models.CategoryRegistry) models.Categories {
+	var categories models.Categories
+	seen := map[string]bool{}
+	for _, category := range registry.Categories() {
+		if category.Subcategory != "" || seen[category.Name] {
+			continue
+		}
+		seen[category.Name] = true
+		categories = append(categories, category)
+	}
+	return categories
+}
+
+func findLabel(labels []*trello.Label, name string) *trello.Label {
+	for _, label := range labels {
+		if strings.EqualFold(strings.TrimSpace(label.Name), name) {
+			return label
+		}
+	}
+	return nil
+}
+
+func cardHasLabel(card *trello.Card, label *trello.Label) bool {
+	if label.ID == "" {
+		return false
+	}
+	for _, id := range card.IDLabels {
+		if id == label.ID {
+			return true
+		}
+	}
+	for _, cardLabel := range card.Labels {
+		if cardLabel.ID == label.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// labelColor returns color when Trello accepts it, no color otherwise
+func labelColor(color string) string {
+	color = strings.ToLower(strings.TrimSpace(color))
+	if trelloLabelColors[color] {
+		return color
+	}
+	return ""
+}
@@ -0,0 +1,118 @@
+package trello
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const (
+	meetingsLabelID      = "6710000000000000000000b2"
+	documentationLabelID = "6710000000000000000000b3"
+)
+
+// newLabelsClient serves the Olympics board with the meetings and
+// documentation labels, both on c2, which is an implementation task
+func newLabelsClient(t *testing.T) (*FakeServer, Client) {
+	t.Helper()
+	fake, client := newFakeClient(t)
+	fixtures := loadFakeFixtures(t)
+
+	var labels []map[string]interface{}
+	json.Unmarshal(fixtures["boards/"+olympicsBoardID+"/labels"], &labels)
+	labels = append(labels,
+		map[string]interface{}{"id": meetingsLabelID, "idBoard": olympicsBoardID, "name": "📅 Catchups / Meetings", "color": "blue"},
+		map[string]interface{}{"id": documentationLabelID, "idBoard": olympicsBoardID, "name": "📧 Emails / Documentation", "color": "yellow"},
+	)
+	fake.Set("boards/"+olympicsBoardID+"/labels", labels)
+
+	var cards []map[string]interface{}
+	json.Unmarshal(fixtures["boards/"+olympicsBoardID+"/cards"], &cards)
+	cards[1]["idLabels"] = []string{"6710000000000000000000b1", documentationLabelID, meetingsLabelID}
+	fake.Set("boards/"+olympicsBoardID+"/cards", cards)
+	return fake, client
+}
+
+func labelChanges(sync LabelSync) []string {
+	var changes []string
+	for _, change := range sync.Changes {
+		changes = append(changes, change.String())
+	}
+	return changes
+}
+
+func TestSyncCategoryLabelsDryRun(t *testing.T) {
+	fake, client := newLabelsClient(t)
+
+	result, err := client.SyncCategoryLabels(olympicsBoardID, october, year2024, LabelOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("error syncing labels: %v", err)
+	}
+	want := []string{
+		`create label "💪 Implementation / Configuration tasks"`,
+		`create label "💬 Slack / Teams Conversations"`,
+		`create label "❓ Other"`,
+		`add label "📅 Catchups / Meetings" on "Weekly call with IOC team"`,
+		`keep label "📅 Catchups / Meetings" on "Fix login redirect (2h)"`,
+		`keep label "📧 Emails / Documentation" on "Fix login redirect (2h)"`,
+		`add label "💪 Implementation / Configuration tasks" on "Documentation for the handover 1h30"`,
+	}
+	assertLabelChanges(t, labelChanges(result), want)
+	if result.Cards != 3 || !result.DryRun {
+		t.Errorf("got %d cards (dry run %v), want the 3 cards of October", result.Cards, result.DryRun)
+	}
+	for _, request := range fake.Requests() {
+		if request[:4] != "GET " {
+			t.Errorf("dry run sent %s", request)
+		}
+	}
+}
+
+func TestSyncCategoryLabelsForce(t *testing.T) {
+	fake, client := newLabelsClient(t)
+
+	result, err := client.SyncCategoryLabels(olympicsBoardID, october, year2024, LabelOptions{Force: true})
+	if err != nil {
+		t.Fatalf("error syncing labels: %v", err)
+	}
+	want := []string{
+		`create label "💪 Implementation / Configuration tasks"`,
+		`create label "💬 Slack / Teams Conversations"`,
+		`create label "❓ Other"`,
+		`add label "📅 Catchups / Meetings" on "Weekly call with IOC team"`,
+		`add label "💪 Implementation / Configuration tasks" on "Fix login redirect (2h)"`,
+		`remove label "📅 Catchups / Meetings" on "Fix login redirect (2h)"`,
+		`remove label "📧 Emails / Documentation" on "Fix login redirect (2h)"`,
+		`add label "💪 Implementation / Configuration tasks" on "Documentation for the handover 1h30"`,
+	}
+	assertLabelChanges(t, labelChanges(result), want)
+
+	var writes []string
+	for _, request := range fake.Requests() {
+		if request[:4] != "GET " {
+			writes = append(writes, request)
+		}
+	}
+	wantWrites := []string{
+		"POST boards/" + olympicsBoardID + "/labels",
+		"POST boards/" + olympicsBoardID + "/labels",
+		"POST boards/" + olympicsBoardID + "/labels",
+		"POST cards/6710000000000000000000c1/idLabels",
+		"POST cards/6710000000000000000000c2/idLabels",
+		"DELETE cards/6710000000000000000000c2/idLabels/" + meetingsLabelID,
+		"DELETE cards/6710000000000000000000c2/idLabels/" + documentationLabelID,
+		"POST cards/6710000000000000000000c3/idLabels",
+	}
+	assertLabelChanges(t, writes, wantWrites)
+}
+
+func assertLabelChanges(t *testing.T, got, want []string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d changes\n%q\nwant %d\n%q", len(got), got, len(want), want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("change %d = %s, want %s", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package trello
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"txeo-tools-library/models"
+
+	"github.com/adlio/trello"
+	"github.com/ozgio/strutil"
+)
+
+var (
+	ErrMonthListNotFound  = errors.New("month list not found")
+	ErrAmbiguousMonthList = errors.New("ambiguous month list")
+	ErrInvalidMonth       = errors.New("invalid month")
+)
+
+// Month names and abbreviations accepted in list names, English and Spanish
+var monthNames = map[string]time.Month{
+	"january": time.January, "jan": time.January, "enero": time.January, "ene": time.January,
+	"february": time.February, "feb": time.February, "febrero": time.February,
+	"march": time.March, "mar": time.March, "marzo": time.March,
+	"april": time.April, "apr": time.April, "abril": time.April, "abr": time.April,
+	"may": time.May, "mayo": time.May,
+	"june": time.June, "jun": time.June, "junio": time.June,
+	"july": time.July, "jul": time.July, "julio": time.July,
+	"august": time.August, "aug": time.August, "agosto": time.August, "ago": time.August,
+	"september": time.September, "sep": time.September, "sept": time.September, "septiembre": time.September, "setiembre": time.September,
+	"october": time.October, "oct": time.October, "octubre": time.October,
+	"november": time.November, "nov": time.November, "noviembre": time.November,
+	"december": time.December, "dec": time.December, "diciembre": time.December, "dic": time.December,
+}
+
+var (
+	yearMonthRegex = regexp.MustCompile(`^(\d{4})-(\d{1,2})$`) // 2024-10
+	monthYearRegex = regexp.MustCompile(`^(\d{1,2})-(\d{4})$`) // 10/2024
+	yearRegex      = regexp.MustCompile(`^\d{4}$`)             // 2024
+	// '24 --> 2024, a bare 24 may be a day
+	apostropheYearRegex = regexp.MustCompile(`['’](\d{2})\b`)
+)
+
+// Words between the month and the year, like in "Octubre de 2024"
+var yearConnectors = map[string]bool{"de": true, "del": true, "of": true}
+
+// ListPeriod is the month a list name refers to, Year is 0 when the name has no year
+type ListPeriod struct {
+	Month time.Month
+	Year  int
+}
+
+func (p ListPeriod) String() string {
+	if p.Year == 0 {
+		return p.Month.String()
+	}
+	return fmt.Sprintf("%s %d", p.Month, p.Year)
+}
+
+// MonthList is a month list of a board with its cards
+type MonthList struct {
+	List   *trello.List
+	Period ListPeriod
+	Cards  []*trello.Card
+}
+
+// ParseListPeriod understands list names like "October", "Octubre 2024",
+// "Octubre de 2024", "2024-10", "Oct '24" or "10/2024".
+func ParseListPeriod(name string) (ListPeriod, bool) {
+	name = apostropheYearRegex.ReplaceAllString(name, " 20$1")
+	slug := strutil.Slugify(strings.NewReplacer("/", "-").Replace(name))
+
+	if groups := yearMonthRegex.FindStringSubmatch(slug); groups != nil {
+		return newListPeriod(groups[2], groups[1])
+	}
+	if groups := monthYearRegex.FindStringSubmatch(slug); groups != nil {
+		return newListPeriod(groups[1], groups[2])
+	}
+
+	var period ListPeriod
+	for _, token := range strings.Split(slug, "-") {
+		if month, ok := monthNames[token]; ok && period.Month == 0 {
+			period.Month = month
+			continue
+		}
+		if yearConnectors[token] && period.Month != 0 && period.Year == 0 {
+			continue
+		}
+		if yearRegex.MatchString(token) && period.Year == 0 && period.Month != 0 {
+			period.Year = parseYear(token)
+			continue
+		}
+		return ListPeriod{}, false
+	}
+	return period, period.Month != 0
+}
+
+// ResolveMonthLists picks the lists holding month of year. "All Year" returns
+// the lists of the twelve months found, and "All Years" (or a zero Year)
+// matches lists with any year as long as there is no ambiguity.
+// A list without year is used only when no list names the year explicitly.
+func ResolveMonthLists(lists []*trello.List, month models.Month, year models.Year) ([]MonthList, error) {
+	if month.Time == 0 && !month.IsAllYear() {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidMonth, month.Name)
+	}
+
+	months := []time.Month{month.Time}
+	if month.IsAllYear() {
+		months = nil
+		for m := time.January; m <= time.December; m++ {
+			months = append(months, m)
+		}
+	}
+
+	var resolved []MonthList
+	for _, m := range months {
+		monthList, err := resolveMonthList(lists, m, year.Number())
+		if errors.Is(err, ErrMonthListNotFound) && month.IsAllYear() {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		resolved = append(resolved, monthList)
+	}
+	if len(resolved) == 0 {
+		return nil, fmt.Errorf("%w: %s %s", ErrMonthListNotFound, month.Name, year.Name)
+	}
+	return resolved, nil
+}
+
+// GetMonthListsAndCards fetches the month lists of a board (open or archived) with their cards
+func GetMonthListsAndCards(client *trello.Client, board *trello.Board, month models.Month, year models.Year) ([]MonthList, error) {
+	return FetchMonthLists(NewAPISource(client), board.ID, month, year)
+}
+
+// FetchMonthLists reads the month lists of a board with their cards from src
+func FetchMonthLists(src Source, boardID string, month models.Month, year models.Year) ([]MonthList, error) {
+	lists, err := src.GetLists(boardID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching lists of board %s: %w", boardID, err)
+	}
+	return fetchMonthListsCards(src, lists, month, year)
+}
+
+/* ╭──────────────────────────────────────────╮ */
+/* │              AUX FUNCTIONS               │ */
+/* ╰──────────────────────────────────────────╯ */
+// fetchMonthListsCards resolves the month lists among lists and reads their cards from src
+func fetchMonthListsCards(src Source, lists []*trello.List, month models.Month, year models.Year) ([]MonthList, error) {
+	monthLists, err := ResolveMonthLists(lists, month, year)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range monthLists {
+		cards, err := src.GetCards(monthLists[i].List.ID)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching cards of list %s: %w", monthLists[i].List.Name, err)
+		}
+		monthLists[i].Cards = cards
+	}
+	return monthLists, nil
+}
+
+func resolveMonthList(lists []*trello.List, month time.Month, year int) (MonthList, error) {
+	var withYear, withoutYear []MonthList
+	for _, list := range lists {
+		period, ok := ParseListPeriod(list.Name)
+		if !ok || period.Month != month {
+			continue
+		}
+		switch {
+		case period.Year == 0:
+			withoutYear = append(withoutYear, MonthList{List: list, Period: period})
+		case year == 0 || period.Year == year:
+			withYear = append(withYear, MonthList{List: list, Period: period})
+		}
+	}
+
+	candidates := withYear
+	if len(candidates) == 0 {
+		candidates = withoutYear
+	}
+	switch len(candidates) {
+	case 0:
+		return MonthList{}, fmt.Errorf("%w: %s %s", ErrMonthListNotFound, month, yearName(year))
+	case 1:
+		if candidates[0].Period.Year == 0 {
+			candidates[0].Period.Year = year
+		}
+		return candidates[0], nil
+	default:
+		var names []string
+		for _, candidate := range candidates {
+			names = append(names, fmt.Sprintf("%q", candidate.List.Name))
+		}
+		return MonthList{}, fmt.Errorf("%w: %s %s matches %s", ErrAmbiguousMonthList, month, yearName(year), strings.Join(names, ", "))
+	}
+}
+
+func newListPeriod(month, year string) (ListPeriod, bool) {
+	m, err := strconv.Atoi(month)
+	if err != nil || m < 1 || m > 12 {
+		return ListPeriod{}, false
+	}
+	return ListPeriod{Month: time.Month(m), Year: parseYear(year)}, true
+}
+
+func parseYear(year string) int {
+	y, _ := strconv.Atoi(year)
+	if y < 100 {
+		y += 2000
+	}
+	return y
+}
+
+func yearName(year int) string {
+	if year == 0 {
+		return "(any year)"
+	}
+	return strconv.Itoa(year)
+}
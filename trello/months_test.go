@@ -0,0 +1,37 @@
+package trello
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseListPeriod(t *testing.T) {
+	tests := []struct {
+		name  string
+		want  ListPeriod
+		found bool
+	}{
+		{"October", ListPeriod{time.October, 0}, true},
+		{"Octubre 2024", ListPeriod{time.October, 2024}, true},
+		{"Octubre de 2024", ListPeriod{time.October, 2024}, true},
+		{"Diciembre del 2024", ListPeriod{time.December, 2024}, true},
+		{"October of 2024", ListPeriod{time.October, 2024}, true},
+		{"Oct '24", ListPeriod{time.October, 2024}, true},
+		{"Oct ’24", ListPeriod{time.October, 2024}, true},
+		{"SEPT 2025", ListPeriod{time.September, 2025}, true},
+		{"2024-10", ListPeriod{time.October, 2024}, true},
+		{"10/2024", ListPeriod{time.October, 2024}, true},
+		{"Octubre 15", ListPeriod{}, false},
+		{"Oct 24", ListPeriod{}, false},
+		{"de Octubre", ListPeriod{}, false},
+		{"Octubre 2024 2025", ListPeriod{}, false},
+		{"13/2024", ListPeriod{}, false},
+		{"Doing", ListPeriod{}, false},
+	}
+	for _, test := range tests {
+		got, found := ParseListPeriod(test.name)
+		if got != test.want || found != test.found {
+			t.Errorf("ParseListPeriod(%q) = %v, %v; want %v, %v", test.name, got, found, test.want, test.found)
+		}
+	}
+}
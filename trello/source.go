@@ -0,0 +1,138 @@
+package trello
+
+import (
+	"fmt"
+
+	"github.com/adlio/trello"
+)
+
+// Source is where board data is read from: the live API, the local cache or a snapshot
+type Source interface {
+	GetBoard(boardID string) (*trello.Board, error)
+	GetLists(boardID string) ([]*trello.List, error)               // Open and archived lists
+	GetCards(listID string) ([]*trello.Card, error)                // Open cards of a list with their custom field items and checklists
+	GetBoardCards(boardID string) ([]*trello.Card, error)          // Open cards of every list of a board
+	GetCustomFields(boardID string) ([]*trello.CustomField, error) // Custom field definitions of a board
+	GetLabels(boardID string) ([]*trello.Label, error)             // Labels defined in a board
+}
+
+// MemberSource is a Source that also knows the members of a board
+type MemberSource interface {
+	GetMembers(boardID string) ([]*trello.Member, error)
+}
+
+// Paths and arguments of every Source call, shared by the API source and the cache
+func boardRequest(boardID string) (string, trello.Arguments) {
+	return fmt.Sprintf("boards/%s", boardID), trello.Defaults()
+}
+func listsRequest(boardID string) (string, trello.Arguments) {
+	return fmt.Sprintf("boards/%s/lists", boardID), trello.Arguments{"filter": "all"}
+}
+func cardsRequest(listID string) (string, trello.Arguments) {
+	return fmt.Sprintf("lists/%s/cards", listID), trello.Arguments{"customFieldItems": "true", "checklists": "all"}
+}
+func boardCardsRequest(boardID string) (string, trello.Arguments) {
+	return fmt.Sprintf("boards/%s/cards", boardID), trello.Arguments{"customFieldItems": "true", "checklists": "all"}
+}
+func customFieldsRequest(boardID string) (string, trello.Arguments) {
+	return fmt.Sprintf("boards/%s/customFields", boardID), trello.Defaults()
+}
+func labelsRequest(boardID string) (string, trello.Arguments) {
+	return fmt.Sprintf("boards/%s/labels", boardID), trello.Defaults()
+}
+func membersRequest(boardID string) (string, trello.Arguments) {
+	return fmt.Sprintf("boards/%s/members", boardID), trello.Defaults()
+}
+
+// apiSource reads straight from the Trello API
+type apiSource struct {
+	client *trello.Client
+}
+
+// NewAPISource reads board data straight from the Trello API
+func NewAPISource(client *trello.Client) Source {
+	return apiSource{client: client}
+}
+
+func (s apiSource) GetBoard(boardID string) (board *trello.Board, err error) {
+	path, args := boardRequest(boardID)
+	err = s.client.Get(path, args, &board)
+	if board != nil {
+		board.SetClient(s.client)
+	}
+	return board, err
+}
+
+func (s apiSource) GetLists(boardID string) (lists []*trello.List, err error) {
+	path, args := listsRequest(boardID)
+	err = s.client.Get(path, args, &lists)
+	for _, list := range lists {
+		list.SetClient(s.client)
+	}
+	return lists, err
+}
+
+func (s apiSource) GetCards(listID string) (cards []*trello.Card, err error) {
+	path, args := cardsRequest(listID)
+	err = s.client.Get(path, args, &cards)
+	for _, card := range cards {
+		card.SetClient(s.client)
+	}
+	return cards, err
+}
+
+func (s apiSource) GetBoardCards(boardID string) (cards []*trello.Card, err error) {
+	path, args := boardCardsRequest(boardID)
+	err = s.client.Get(path, args, &cards)
+	for _, card := range cards {
+		card.SetClient(s.client)
+	}
+	return cards, err
+}
+
+func (s apiSource) GetCustomFields(boardID string) (customFields []*trello.CustomField, err error) {
+	path, args := customFieldsRequest(boardID)
+	err = s.client.Get(path, args, &customFields)
+	return customFields, err
+}
+
+func (s apiSource) GetLabels(boardID string) (labels []*trello.Label, err error) {
+	path, args := labelsRequest(boardID)
+	err = s.client.Get(path, args, &labels)
+	for _, label := range labels {
+		label.SetClient(s.client)
+	}
+	return labels, err
+}
+
+func (s apiSource) GetMembers(boardID string) (members []*trello.Member, err error) {
+	path, args := membersRequest(boardID)
+	err = s.client.Get(path, args, &members)
+	for _, member := range members {
+		member.SetClient(s.client)
+	}
+	return members, err
+}
+
+// Client reads straight from the API too
+func (c Client) GetBoard(boardID string) (*trello.Board, error) {
+	return NewAPISource(c.API).GetBoard(boardID)
+}
+func (c Client) GetLists(boardID string) ([]*trello.List, error) {
+	return NewAPISource(c.API).GetLists(boardID)
+}
+func (c Client) GetCards(listID string) ([]*trello.Card, error) {
+	return NewAPISource(c.API).GetCards(listID)
+}
+func (c Client) GetBoardCards(boardID string) ([]*trello.Card, error) {
+	return NewAPISource(c.API).GetBoardCards(boardID)
+}
+func (c Client) GetCustomFields(boardID string) ([]*trello.CustomField, error) {
+	return NewAPISource(c.API).GetCustomFields(boardID)
+}
+func (c Client) GetLabels(boardID string) ([]*trello.Label, error) {
+	return NewAPISource(c.API).GetLabels(boardID)
+}
+func (c Client) GetMembers(boardID string) ([]*trello.Member, error) {
+	return apiSource{client: c.API}.GetMembers(boardID)
+}
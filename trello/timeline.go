@@ -0,0 +1,252 @@
+package trello
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/adlio/trello"
+)
+
+// listChangeActions are the card actions that move a card in or out of a list
+const listChangeActions = "createCard,copyCard,updateCard:idList,updateCard:closed"
+
+// ListSpan is a stay of a card in a list
+type ListSpan struct {
+	ListID   string
+	ListName string
+	Start    time.Time
+	End      time.Time
+	Open     bool // The card is still in the list, End is the time the timeline was built
+}
+
+// CardTimeline is the history of the lists a card has been in
+type CardTimeline struct {
+	CardID   string
+	CardName string
+	BoardID  string
+	Spans    []ListSpan
+}
+
+// ListTime is the time cards spent in a list of a board during a month
+type ListTime struct {
+	BoardID  string
+	Year     int
+	Month    time.Month
+	ListID   string
+	ListName string
+	Duration time.Duration
+	Cards    int
+}
+
+// BuildCardTimeline rebuilds the lists a card has been in from its list change
+// actions (createCard, updateCard:idList and updateCard:closed). Archiving a
+// card ends its stay in the list, and the last stay ends at now.
+func BuildCardTimeline(card *trello.Card, actions []*trello.Action, now time.Time) CardTimeline {
+	timeline := CardTimeline{CardID: card.ID, CardName: card.Name, BoardID: card.IDBoard}
+
+	actions = append([]*trello.Action{}, actions...)
+	sort.SliceStable(actions, func(i, j int) bool { return actions[i].Date.Before(actions[j].Date) })
+
+	var current *ListSpan
+	closeSpan := func(end time.Time) {
+		if current == nil {
+			return
+		}
+		current.End = end
+		timeline.Spans = append(timeline.Spans, *current)
+		current = nil
+	}
+	openSpan := func(list *trello.List, start time.Time) {
+		if list == nil {
+			return
+		}
+		current = &ListSpan{ListID: list.ID, ListName: list.Name, Start: start}
+	}
+
+	// Created before the history we got, the card was in the list it was first
+	// moved from, unless it was archived then
+	lastList := &trello.List{ID: card.IDList}
+	archived, closedChanged := false, false
+initial:
+	for _, action := range actions {
+		switch {
+		case action.Data == nil:
+			continue
+		case action.Type == "createCard" || action.Type == "copyCard":
+			break initial
+		case action.Data.ListBefore != nil:
+			lastList = action.Data.ListBefore
+			if !archived {
+				openSpan(lastList, card.CreatedAt())
+			}
+			break initial
+		case action.Data.Card != nil && action.Data.ListAfter == nil && !closedChanged:
+			// Unarchived first, it was archived when the history starts
+			archived, closedChanged = !action.Data.Card.Closed, true
+		}
+	}
+
+	for _, action := range actions {
+		if action.Data == nil {
+			continue
+		}
+		switch {
+		case action.Type == "createCard" || action.Type == "copyCard":
+			closeSpan(action.Date)
+			openSpan(action.Data.List, action.Date)
+		case action.Data.ListAfter != nil:
+			closeSpan(action.Date)
+			openSpan(action.Data.ListAfter, action.Date)
+		case action.Data.Card != nil && action.Data.Card.Closed:
+			closeSpan(action.Date)
+		case action.Data.Card != nil:
+			// Unarchived, back to the list it was in
+			if current == nil {
+				openSpan(lastList, action.Date)
+			}
+		}
+		if current != nil {
+			lastList = &trello.List{ID: current.ListID, Name: current.ListName}
+		}
+	}
+
+	if len(actions) == 0 && card.IDList != "" && !card.Closed {
+		openSpan(&trello.List{ID: card.IDList}, card.CreatedAt())
+	}
+	if current != nil {
+		current.Open = true
+		closeSpan(now)
+	}
+	return timeline
+}
+
+// TimeInLists returns the time spent in every list of the timeline, keyed by
+// list ID, counting only working hours when calendar isn't nil
+func (t CardTimeline) TimeInLists(calendar *WorkCalendar) map[string]time.Duration {
+	durations := map[string]time.Duration{}
+	for _, span := range t.Spans {
+		durations[span.ListID] += spanDuration(span.Start, span.End, calendar)
+	}
+	return durations
+}
+
+// GetCardTimeline fetches the list change actions of a card and builds its timeline
+func (c Client) GetCardTimeline(card *trello.Card, now time.Time) (CardTimeline, error) {
+	var actions []*trello.Action
+	args := trello.Arguments{"filter": listChangeActions, "limit": "1000"}
+	if err := c.API.Get(fmt.Sprintf("cards/%s/actions", card.ID), args, &actions); err != nil {
+		return CardTimeline{}, fmt.Errorf("error fetching actions of card %s: %w", card.Name, err)
+	}
+	return BuildCardTimeline(card, actions, now), nil
+}
+
+// GetCardTimelines builds the timeline of every card, naming the lists with
+// their current names
+func (c Client) GetCardTimelines(boardID string, cards []*trello.Card, now time.Time) ([]CardTimeline, error) {
+	lists, err := c.GetLists(boardID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching lists of board %s: %w", boardID, err)
+	}
+	names := map[string]string{}
+	for _, list := range lists {
+		names[list.ID] = list.Name
+	}
+
+	var timelines []CardTimeline
+	for _, card := range cards {
+		timeline, err := c.GetCardTimeline(card, now)
+		if err != nil {
+			return timelines, err
+		}
+		if timeline.BoardID == "" {
+			timeline.BoardID = boardID
+		}
+		for i, span := range timeline.Spans {
+			if name, ok := names[span.ListID]; ok {
+				timeline.Spans[i].ListName = name
+			}
+		}
+		timelines = append(timelines, timeline)
+	}
+	return timelines, nil
+}
+
+// AggregateListTimes adds the time the cards spent in each list up per board
+// and month, splitting the stays that cross a month. Only working hours count
+// when calendar isn't nil, months are taken in the calendar location (UTC otherwise).
+func AggregateListTimes(timelines []CardTimeline, calendar *WorkCalendar) []ListTime {
+	location := time.UTC
+	if calendar != nil {
+		location = calendar.location()
+	}
+
+	type key struct {
+		boardID string
+		year    int
+		month   time.Month
+		listID  string
+	}
+	totals := map[key]*ListTime{}
+	cards := map[key]map[string]bool{}
+
+	for _, timeline := range timelines {
+		for _, span := range timeline.Spans {
+			start, end := span.Start.In(location), span.End.In(location)
+			for start.Before(end) {
+				monthEnd := time.Date(start.Year(), start.Month()+1, 1, 0, 0, 0, 0, location)
+				if end.Before(monthEnd) {
+					monthEnd = end
+				}
+
+				k := key{boardID: timeline.BoardID, year: start.Year(), month: start.Month(), listID: span.ListID}
+				total, ok := totals[k]
+				if !ok {
+					total = &ListTime{BoardID: k.boardID, Year: k.year, Month: k.month, ListID: k.listID}
+					totals[k] = total
+					cards[k] = map[string]bool{}
+				}
+				if span.ListName != "" {
+					total.ListName = span.ListName
+				}
+				total.Duration += spanDuration(start, monthEnd, calendar)
+				cards[k][timeline.CardID] = true
+				total.Cards = len(cards[k])
+
+				start = monthEnd
+			}
+		}
+	}
+
+	listTimes := make([]ListTime, 0, len(totals))
+	for _, total := range totals {
+		listTimes = append(listTimes, *total)
+	}
+	sort.Slice(listTimes, func(i, j int) bool {
+		a, b := listTimes[i], listTimes[j]
+		if a.BoardID != b.BoardID {
+			return a.BoardID < b.BoardID
+		}
+		if a.Year != b.Year {
+			return a.Year < b.Year
+		}
+		if a.Month != b.Month {
+			return a.Month < b.Month
+		}
+		return a.ListName < b.ListName
+	})
+	return listTimes
+}
+
+/* ╭──────────────────────────────────────────╮ */
+/* │              AUX FUNCTIONS               │ */
+/* ╰──────────────────────────────────────────╯ */
+func spanDuration(start, end time.Time, calendar *WorkCalendar) time.Duration {
+	if calendar == nil {
+		if end.After(start) {
+			return end.Sub(start)
+		}
+		return 0
+	}
+	return calendar.Between(start, end)
+}
@@ -0,0 +1,158 @@
+package trello
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/adlio/trello"
+)
+
+func TestWorkCalendarBetween(t *testing.T) {
+	madrid, err := time.LoadLocation("Europe/Madrid")
+	if err != nil {
+		t.Fatalf("error loading location: %v", err)
+	}
+	utc := DefaultWorkCalendar()
+	utc.Location = time.UTC
+	utc.Holidays = []string{"2024-12-25"}
+	everyDay := DefaultWorkCalendar()
+	everyDay.Location = madrid
+	everyDay.Weekdays = append(everyDay.Weekdays, time.Saturday, time.Sunday)
+
+	tests := []struct {
+		name     string
+		calendar WorkCalendar
+		from, to time.Time
+		want     time.Duration
+	}{
+		{"within a day", utc, time.Date(2024, 11, 5, 10, 0, 0, 0, time.UTC), time.Date(2024, 11, 5, 12, 30, 0, 0, time.UTC), 150 * time.Minute},
+		{"before and after hours", utc, time.Date(2024, 11, 5, 7, 0, 0, 0, time.UTC), time.Date(2024, 11, 5, 21, 0, 0, 0, time.UTC), 9 * time.Hour},
+		{"over the weekend", utc, time.Date(2024, 11, 8, 17, 0, 0, 0, time.UTC), time.Date(2024, 11, 11, 10, 0, 0, 0, time.UTC), 2 * time.Hour},
+		{"holiday", utc, time.Date(2024, 12, 24, 0, 0, 0, 0, time.UTC), time.Date(2024, 12, 27, 0, 0, 0, 0, time.UTC), 18 * time.Hour},
+		{"backwards", utc, time.Date(2024, 11, 6, 0, 0, 0, 0, time.UTC), time.Date(2024, 11, 5, 0, 0, 0, 0, time.UTC), 0},
+		// Clocks go forward at 2:00, the day still starts at 9:00
+		{"summer time starts", everyDay, time.Date(2024, 3, 31, 9, 30, 0, 0, madrid), time.Date(2024, 3, 31, 23, 0, 0, 0, madrid), 510 * time.Minute},
+		// Clocks go back at 3:00, the day still ends at 18:00
+		{"summer time ends", everyDay, time.Date(2024, 10, 27, 0, 0, 0, 0, madrid), time.Date(2024, 10, 27, 17, 0, 0, 0, madrid), 8 * time.Hour},
+	}
+	for _, test := range tests {
+		if got := test.calendar.Between(test.from, test.to); got != test.want {
+			t.Errorf("%s: got %s, want %s", test.name, got, test.want)
+		}
+	}
+}
+
+func TestBuildCardTimeline(t *testing.T) {
+	_, client := newFakeClient(t)
+	card := &trello.Card{ID: "6710000000000000000000c5", Name: "Screensets styling [3]", IDList: "6710000000000000000000a4"}
+	now := time.Date(2024, 11, 12, 12, 0, 0, 0, time.UTC)
+
+	timeline, err := client.GetCardTimeline(card, now)
+	if err != nil {
+		t.Fatalf("error building timeline: %v", err)
+	}
+	if len(timeline.Spans) != 2 {
+		t.Fatalf("got spans %+v, want Noviembre 2024 then Doing", timeline.Spans)
+	}
+	created, moved := time.Date(2024, 11, 5, 9, 0, 0, 0, time.UTC), time.Date(2024, 11, 11, 10, 0, 0, 0, time.UTC)
+	if span := timeline.Spans[0]; span.ListName != "Noviembre 2024" || !span.Start.Equal(created) || !span.End.Equal(moved) || span.Open {
+		t.Errorf("first span = %+v", span)
+	}
+	if span := timeline.Spans[1]; span.ListName != "Doing" || !span.Start.Equal(moved) || !span.End.Equal(now) || !span.Open {
+		t.Errorf("second span = %+v", span)
+	}
+
+	calendar := DefaultWorkCalendar()
+	calendar.Location = time.UTC
+	for _, test := range []struct {
+		calendar     *WorkCalendar
+		month, doing time.Duration
+	}{
+		{nil, 6*24*time.Hour + time.Hour, 26 * time.Hour},
+		{&calendar, 37 * time.Hour, 11 * time.Hour},
+	} {
+		durations := timeline.TimeInLists(test.calendar)
+		if durations["6710000000000000000000a3"] != test.month || durations["6710000000000000000000a4"] != test.doing {
+			t.Errorf("working hours %v: got %v, want %s in the month list and %s in Doing", test.calendar != nil, durations, test.month, test.doing)
+		}
+	}
+}
+
+func TestBuildCardTimelineArchived(t *testing.T) {
+	card := &trello.Card{ID: "6710000000000000000000c7", IDList: "6710000000000000000000a4"}
+	list := &trello.List{ID: "6710000000000000000000a4", Name: "Doing"}
+	at := func(day, hour int) time.Time { return time.Date(2024, 11, day, hour, 0, 0, 0, time.UTC) }
+	actions := []*trello.Action{
+		{Type: "updateCard", Date: at(4, 9), Data: &trello.ActionData{Card: &trello.ActionDataCard{Closed: false}}},
+		{Type: "updateCard", Date: at(2, 9), Data: &trello.ActionData{Card: &trello.ActionDataCard{Closed: true}}},
+		{Type: "createCard", Date: at(1, 9), Data: &trello.ActionData{List: list}},
+	}
+
+	timeline := BuildCardTimeline(card, actions, at(5, 9))
+	if len(timeline.Spans) != 2 {
+		t.Fatalf("got spans %+v, want the stays before and after being archived", timeline.Spans)
+	}
+	if got := timeline.TimeInLists(nil)[list.ID]; got != 48*time.Hour {
+		t.Errorf("got %s in Doing, want 48h without the 2 days archived", got)
+	}
+}
+
+func TestBuildCardTimelineHistoryStartsArchiving(t *testing.T) {
+	card := &trello.Card{ID: "6710000000000000000000c7", IDList: "6710000000000000000000a4"}
+	month := &trello.List{ID: "6710000000000000000000a3", Name: "Noviembre 2024"}
+	doing := &trello.List{ID: "6710000000000000000000a4", Name: "Doing"}
+	at := func(day, hour int) time.Time { return time.Date(2024, 11, day, hour, 0, 0, 0, time.UTC) }
+	actions := []*trello.Action{
+		{Type: "updateCard", Date: at(2, 9), Data: &trello.ActionData{Card: &trello.ActionDataCard{Closed: true}}},
+		{Type: "updateCard", Date: at(3, 9), Data: &trello.ActionData{Card: &trello.ActionDataCard{Closed: false}}},
+		{Type: "updateCard", Date: at(4, 9), Data: &trello.ActionData{ListBefore: month, ListAfter: doing}},
+	}
+
+	timeline := BuildCardTimeline(card, actions, at(5, 9))
+	if len(timeline.Spans) != 3 {
+		t.Fatalf("got spans %+v, want Noviembre 2024 before and after being archived, then Doing", timeline.Spans)
+	}
+	if span := timeline.Spans[0]; span.ListID != month.ID || !span.Start.Equal(card.CreatedAt()) || !span.End.Equal(at(2, 9)) {
+		t.Errorf("first span = %+v, want Noviembre 2024 from the creation of the card", span)
+	}
+	if span := timeline.Spans[1]; span.ListID != month.ID || !span.Start.Equal(at(3, 9)) || !span.End.Equal(at(4, 9)) {
+		t.Errorf("second span = %+v, want Noviembre 2024 once unarchived", span)
+	}
+	if span := timeline.Spans[2]; span.ListID != doing.ID || !span.Open {
+		t.Errorf("third span = %+v, want the open stay in Doing", span)
+	}
+
+	// Archived when the history starts, it wasn't in any list until unarchived
+	timeline = BuildCardTimeline(card, actions[1:], at(5, 9))
+	if len(timeline.Spans) != 2 || !timeline.Spans[0].Start.Equal(at(3, 9)) {
+		t.Errorf("got spans %+v, want Noviembre 2024 from the unarchiving, then Doing", timeline.Spans)
+	}
+}
+
+func TestAggregateListTimes(t *testing.T) {
+	timelines := []CardTimeline{
+		{CardID: "c1", BoardID: olympicsBoardID, Spans: []ListSpan{
+			{ListID: "a4", ListName: "Doing", Start: time.Date(2024, 10, 31, 12, 0, 0, 0, time.UTC), End: time.Date(2024, 11, 1, 18, 0, 0, 0, time.UTC)},
+		}},
+		{CardID: "c2", BoardID: olympicsBoardID, Spans: []ListSpan{
+			{ListID: "a4", ListName: "Doing", Start: time.Date(2024, 11, 4, 9, 0, 0, 0, time.UTC), End: time.Date(2024, 11, 4, 11, 0, 0, 0, time.UTC)},
+			{ListID: "a5", ListName: "Review", Start: time.Date(2024, 11, 4, 11, 0, 0, 0, time.UTC), End: time.Date(2024, 11, 4, 12, 0, 0, 0, time.UTC)},
+		}},
+	}
+
+	got := AggregateListTimes(timelines, nil)
+	want := []ListTime{
+		{BoardID: olympicsBoardID, Year: 2024, Month: time.October, ListID: "a4", ListName: "Doing", Duration: 12 * time.Hour, Cards: 1},
+		{BoardID: olympicsBoardID, Year: 2024, Month: time.November, ListID: "a4", ListName: "Doing", Duration: 20 * time.Hour, Cards: 2},
+		{BoardID: olympicsBoardID, Year: 2024, Month: time.November, ListID: "a5", ListName: "Review", Duration: time.Hour, Cards: 1},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("list time %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package trello
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+)
+
+// Trello allows 100 requests every 10 seconds per token
+const (
+	DefaultRequestsPerSecond = 10
+	DefaultBurst             = 10
+	DefaultMaxRetries        = 5
+	DefaultMinBackoff        = 500 * time.Millisecond
+	DefaultMaxBackoff        = 30 * time.Second
+)
+
+// TransportOptions configures the rate limit and the retries of the Trello transport
+type TransportOptions struct {
+	Base              http.RoundTripper // http.DefaultTransport when nil
+	RequestsPerSecond float64           // Client-side token bucket rate, DefaultRequestsPerSecond when zero
+	Burst             int               // Token bucket size, DefaultBurst when zero
+	MaxRetries        int               // Retries of 429 responses, and of 5xx and network errors of GET and HEAD requests, DefaultMaxRetries when zero, none when negative
+	MinBackoff        time.Duration     // First backoff, doubled on every retry
+	MaxBackoff        time.Duration     // Limit of the backoff and of Retry-After
+	Logger            logrus.FieldLogger
+}
+
+// Transport is an http.RoundTripper that keeps under the Trello rate limits and
+// retries rate limited requests, and failed reads, with exponential backoff and
+// jitter, honoring Retry-After.
+type Transport struct {
+	options TransportOptions
+	limiter *rate.Limiter
+}
+
+// RequestError is returned when a request keeps failing after every retry
+type RequestError struct {
+	Method     string
+	Path       string
+	StatusCode int // 0 when no response was received
+	Attempts   int
+	Err        error
+}
+
+func (e *RequestError) Error() string {
+	return fmt.Sprintf("trello %s %s failed after %d attempts: %v", e.Method, e.Path, e.Attempts, e.Err)
+}
+
+func (e *RequestError) Unwrap() error { return e.Err }
+
+// IsRateLimit tells whether Trello kept answering 429
+func (e *RequestError) IsRateLimit() bool { return e.StatusCode == http.StatusTooManyRequests }
+
+// AsRequestError finds a RequestError in the chain of err, following both
+// errors.Unwrap and the Cause of the errors wrapped by the adlio client
+func AsRequestError(err error) (*RequestError, bool) {
+	for err != nil {
+		var requestError *RequestError
+		if errors.As(err, &requestError) {
+			return requestError, true
+		}
+		causer, ok := err.(interface{ Cause() error })
+		if !ok {
+			return nil, false
+		}
+		err = causer.Cause()
+	}
+	return nil, false
+}
+
+func NewTransport(options TransportOptions) *Transport {
+	if options.Base == nil {
+		options.Base = http.DefaultTransport
+	}
+	if options.RequestsPerSecond == 0 {
+		options.RequestsPerSecond = DefaultRequestsPerSecond
+	}
+	if options.Burst == 0 {
+		options.Burst = DefaultBurst
+	}
+	if options.MaxRetries == 0 {
+		options.MaxRetries = DefaultMaxRetries
+	}
+	if options.MinBackoff == 0 {
+		options.MinBackoff = DefaultMinBackoff
+	}
+	if options.MaxBackoff == 0 {
+		options.MaxBackoff = DefaultMaxBackoff
+	}
+	return &Transport{
+		options: options,
+		limiter: rate.NewLimiter(rate.Limit(options.RequestsPerSecond), options.Burst),
+	}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	requestError := &RequestError{Method: req.Method, Path: req.URL.Path}
+
+	for {
+		requestError.Attempts++
+		if err := t.limiter.Wait(ctx); err != nil {
+			requestError.Err = err
+			return nil, requestError
+		}
+
+		attempt, err := cloneRequest(req)
+		if err != nil {
+			requestError.Err = err
+			return nil, requestError
+		}
+		resp, err := t.options.Base.RoundTrip(attempt)
+
+		var delay time.Duration
+		switch {
+		case err != nil:
+			if ctx.Err() != nil {
+				requestError.Err = ctx.Err()
+				return nil, requestError
+			}
+			requestError.StatusCode, requestError.Err = 0, err
+			delay = t.backoff(requestError.Attempts)
+		case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+			body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+			resp.Body.Close()
+			requestError.StatusCode = resp.StatusCode
+			requestError.Err = fmt.Errorf("%s: %s", resp.Status, body)
+			delay = t.retryAfter(resp, requestError.Attempts)
+		default:
+			return resp, nil
+		}
+
+		// A write may have reached Trello before failing, retrying it could
+		// duplicate comments, labels or lists. Rate limited ones were not processed.
+		if !idempotent(req.Method) && requestError.StatusCode != http.StatusTooManyRequests {
+			return nil, requestError
+		}
+		if requestError.Attempts > t.options.MaxRetries {
+			return nil, requestError
+		}
+
+		if t.options.Logger != nil {
+			t.options.Logger.Debugf("[trello] %s %s attempt %d failed (%v), retrying in %s", req.Method, req.URL.Path, requestError.Attempts, requestError.Err, delay)
+		}
+		if err := sleep(ctx, delay); err != nil {
+			requestError.Err = err
+			return nil, requestError
+		}
+	}
+}
+
+/* ╭──────────────────────────────────────────╮ */
+/* │              AUX FUNCTIONS               │ */
+/* ╰──────────────────────────────────────────╯ */
+// backoff is an exponential backoff with jitter between half and the full delay
+func (t *Transport) backoff(attempt int) time.Duration {
+	delay := t.options.MinBackoff << (attempt - 1)
+	if delay > t.options.MaxBackoff || delay <= 0 {
+		delay = t.options.MaxBackoff
+	}
+	half := int64(delay / 2)
+	return time.Duration(half + rand.Int63n(half+1))
+}
+
+// retryAfter honors the Retry-After header (seconds or HTTP date) when present
+func (t *Transport) retryAfter(resp *http.Response, attempt int) time.Duration {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return t.backoff(attempt)
+	}
+	var delay time.Duration
+	if seconds, err := strconv.Atoi(value); err == nil {
+		delay = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(value); err == nil {
+		delay = time.Until(date)
+	} else {
+		return t.backoff(attempt)
+	}
+	if delay < 0 {
+		delay = 0
+	}
+	if delay > t.options.MaxBackoff {
+		delay = t.options.MaxBackoff
+	}
+	return delay
+}
+
+// idempotent tells whether a request can be sent again after a failure
+func idempotent(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead
+}
+
+func cloneRequest(req *http.Request) (*http.Request, error) {
+	clone := req.Clone(req.Context())
+	if req.Body != nil && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		clone.Body = body
+	}
+	return clone, nil
+}
+
+func sleep(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,180 @@
+package trello
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// scriptedServer answers with the given status codes in turn, 200 once they run out
+type scriptedServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	statuses []int
+	headers  map[string]string
+	requests int
+}
+
+func newScriptedServer(t *testing.T, statuses ...int) *scriptedServer {
+	t.Helper()
+	server := &scriptedServer{statuses: statuses, headers: map[string]string{}}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.mu.Lock()
+		defer server.mu.Unlock()
+		server.requests++
+		status := http.StatusOK
+		if len(server.statuses) > 0 {
+			status, server.statuses = server.statuses[0], server.statuses[1:]
+		}
+		for key, value := range server.headers {
+			w.Header().Set(key, value)
+		}
+		w.WriteHeader(status)
+		w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func (s *scriptedServer) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+func testTransport(base http.RoundTripper, maxRetries int) *Transport {
+	return NewTransport(TransportOptions{
+		Base:              base,
+		RequestsPerSecond: 1000,
+		Burst:             100,
+		MaxRetries:        maxRetries,
+		MinBackoff:        time.Millisecond,
+		MaxBackoff:        5 * time.Millisecond,
+	})
+}
+
+func TestTransportRetries(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		statuses   []int
+		maxRetries int
+		requests   int
+		status     int // Status of the RequestError, 0 when the request succeeds
+	}{
+		{"get retried on 5xx", http.MethodGet, []int{500, 502}, 0, 3, 0},
+		{"get retried on 429", http.MethodGet, []int{429}, 0, 2, 0},
+		{"get gives up after max retries", http.MethodGet, []int{503, 503, 503, 503}, 2, 3, 503},
+		{"no retries when negative", http.MethodGet, []int{503}, -1, 1, 503},
+		{"post not retried on 5xx", http.MethodPost, []int{502}, 0, 1, 502},
+		{"put not retried on 5xx", http.MethodPut, []int{500}, 0, 1, 500},
+		{"post retried on 429", http.MethodPost, []int{429, 429}, 0, 3, 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := newScriptedServer(t, test.statuses...)
+			client := &http.Client{Transport: testTransport(http.DefaultTransport, test.maxRetries)}
+
+			request, _ := http.NewRequest(test.method, server.URL+"/1/cards", strings.NewReader("name=card"))
+			resp, err := client.Do(request)
+			if test.status == 0 {
+				if err != nil {
+					t.Fatalf("got %v, want success", err)
+				}
+				resp.Body.Close()
+			} else {
+				requestError, ok := AsRequestError(err)
+				if !ok {
+					t.Fatalf("got %v, want a RequestError", err)
+				}
+				if requestError.StatusCode != test.status || requestError.Attempts != test.requests {
+					t.Errorf("got status %d after %d attempts, want %d after %d", requestError.StatusCode, requestError.Attempts, test.status, test.requests)
+				}
+			}
+			if server.count() != test.requests {
+				t.Errorf("server got %d requests, want %d", server.count(), test.requests)
+			}
+		})
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestTransportNetworkErrors(t *testing.T) {
+	for _, test := range []struct {
+		method   string
+		attempts int
+	}{
+		{http.MethodGet, 3},
+		{http.MethodHead, 3},
+		{http.MethodPost, 1},
+	} {
+		attempts := 0
+		base := roundTripFunc(func(*http.Request) (*http.Response, error) {
+			attempts++
+			return nil, errors.New("connection reset by peer")
+		})
+		request, _ := http.NewRequest(test.method, "http://trello.invalid/1/cards", nil)
+		_, err := testTransport(base, 2).RoundTrip(request)
+		requestError, ok := AsRequestError(err)
+		if !ok || requestError.StatusCode != 0 {
+			t.Fatalf("%s: got %v, want a RequestError without status", test.method, err)
+		}
+		if attempts != test.attempts {
+			t.Errorf("%s: got %d attempts, want %d", test.method, attempts, test.attempts)
+		}
+	}
+}
+
+func TestTransportRetryAfter(t *testing.T) {
+	transport := NewTransport(TransportOptions{MinBackoff: 100 * time.Millisecond, MaxBackoff: 10 * time.Second})
+	response := func(value string) *http.Response {
+		return &http.Response{Header: http.Header{"Retry-After": []string{value}}}
+	}
+
+	if delay := transport.retryAfter(response("3"), 1); delay != 3*time.Second {
+		t.Errorf("Retry-After 3 waits %s, want 3s", delay)
+	}
+	if delay := transport.retryAfter(response("120"), 1); delay != 10*time.Second {
+		t.Errorf("Retry-After 120 waits %s, want the 10s max backoff", delay)
+	}
+	date := time.Now().Add(5 * time.Second).UTC().Format(http.TimeFormat)
+	if delay := transport.retryAfter(response(date), 1); delay < 3*time.Second || delay > 5*time.Second {
+		t.Errorf("Retry-After date waits %s, want about 5s", delay)
+	}
+	if delay := transport.retryAfter(response("soon"), 1); delay < 50*time.Millisecond || delay > 100*time.Millisecond {
+		t.Errorf("invalid Retry-After waits %s, want the backoff", delay)
+	}
+
+	// The server asks to wait before the retry
+	server := newScriptedServer(t, http.StatusTooManyRequests)
+	server.headers["Retry-After"] = "1"
+	client := &http.Client{Transport: NewTransport(TransportOptions{MinBackoff: time.Millisecond, MaxBackoff: 2 * time.Second})}
+	start := time.Now()
+	resp, err := client.Get(server.URL + "/1/boards")
+	if err != nil {
+		t.Fatalf("got %v, want success", err)
+	}
+	resp.Body.Close()
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %s, want the 1s of Retry-After", elapsed)
+	}
+}
+
+func TestTransportBackoff(t *testing.T) {
+	transport := NewTransport(TransportOptions{MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second})
+	for attempt, max := range []time.Duration{100, 200, 400, 800, 1000, 1000} {
+		max *= time.Millisecond
+		for i := 0; i < 20; i++ {
+			if delay := transport.backoff(attempt + 1); delay < max/2 || delay > max {
+				t.Fatalf("attempt %d waits %s, want between %s and %s", attempt+1, delay, max/2, max)
+			}
+		}
+	}
+}
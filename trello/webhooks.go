@@ -0,0 +1,289 @@
+package trello
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/adlio/trello"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// Card events parsed from webhook actions
+const (
+	CardCreated  = "create"
+	CardAdded    = "add" // Moved or copied from another board, sent to the board it arrived to
+	CardUpdated  = "update"
+	CardMoved    = "move"
+	CardArchived = "archive"
+	CardRestored = "restore"
+	CardDeleted  = "delete"
+	CardRemoved  = "remove" // Moved to another board, sent to the board it left
+)
+
+// WebhookSignatureHeader holds the signature of the webhook callbacks
+const WebhookSignatureHeader = "X-Trello-Webhook"
+
+var (
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	ErrNoWebhookSecret  = errors.New("webhook secret not set")
+)
+
+// maxWebhookBody is the largest webhook payload accepted
+const maxWebhookBody = 1 << 20
+
+// CardEvent is a change of a card notified by a webhook
+type CardEvent struct {
+	Type     string
+	ActionID string
+	Date     time.Time
+	BoardID  string
+	ListID   string // List of the card after the action
+	CardID   string
+	Name     string
+	Desc     string
+	Due      *time.Time
+	Closed   bool
+	Changed  []string // Card attributes changed by an update, as named by Trello
+}
+
+// HasChanged tells whether an update changed a card attribute. Every
+// attribute of a created card is new, the ones of a card added from another
+// board are only known when the action sends them as changed.
+func (e CardEvent) HasChanged(attribute string) bool {
+	if e.Type == CardCreated {
+		return true
+	}
+	for _, changed := range e.Changed {
+		if changed == attribute {
+			return true
+		}
+	}
+	return false
+}
+
+// CardStore keeps a local copy of the cards up to date with the webhook events
+type CardStore interface {
+	ApplyCardEvent(event CardEvent) error
+}
+
+// WebhookHandler receives Trello webhooks and applies card events to a store
+type WebhookHandler struct {
+	Secret      string // App secret used by Trello to sign the callbacks, every callback is rejected when empty
+	CallbackURL string // URL registered in Trello, part of the signed content
+	Store       CardStore
+	Logger      logrus.FieldLogger
+}
+
+// NewWebhookHandler creates a handler for the webhooks registered with callbackURL
+func NewWebhookHandler(secret, callbackURL string, store CardStore) *WebhookHandler {
+	return &WebhookHandler{Secret: secret, CallbackURL: callbackURL, Store: store}
+}
+
+// Register adds the webhook routes: HEAD, used by Trello to check the
+// callback when the webhook is created, and POST with the actions.
+func (h *WebhookHandler) Register(router gin.IRoutes, path string) {
+	router.HEAD(path, h.Check)
+	router.POST(path, h.Handle)
+}
+
+// Check answers the HEAD request Trello makes before creating a webhook
+func (h *WebhookHandler) Check(c *gin.Context) {
+	c.Status(http.StatusOK)
+}
+
+// Handle verifies the signature of an action and applies it to the store.
+// Actions not about cards are acknowledged and ignored.
+func (h *WebhookHandler) Handle(c *gin.Context) {
+	if h.Secret == "" {
+		h.log().Error("Trello webhook rejected, the handler has no secret")
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": ErrNoWebhookSecret.Error()})
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookBody))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !VerifyWebhookSignature(h.Secret, h.CallbackURL, body, c.GetHeader(WebhookSignatureHeader)) {
+		h.log().Warn("Trello webhook with an invalid signature")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": ErrInvalidSignature.Error()})
+		return
+	}
+
+	event, ok, err := ParseWebhookAction(body)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !ok {
+		c.Status(http.StatusOK)
+		return
+	}
+
+	if err := h.Store.ApplyCardEvent(event); err != nil {
+		h.log().WithError(err).WithField("action", event.ActionID).Error("Error applying Trello card event")
+		// Trello retries the callback when it doesn't get a 2xx
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	h.log().WithFields(logrus.Fields{"event": event.Type, "card": event.CardID}).Debug("Trello card event applied")
+	c.Status(http.StatusOK)
+}
+
+// VerifyWebhookSignature checks the base64 HMAC-SHA1 of body followed by the
+// callback URL, signed with the app secret
+func VerifyWebhookSignature(secret, callbackURL string, body []byte, signature string) bool {
+	expected, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal(expected, webhookDigest(secret, callbackURL, body))
+}
+
+// SignWebhook returns the signature Trello sends for body
+func SignWebhook(secret, callbackURL string, body []byte) string {
+	return base64.StdEncoding.EncodeToString(webhookDigest(secret, callbackURL, body))
+}
+
+// ParseWebhookAction reads the action of a webhook payload. ok is false when
+// the action isn't a card create, copy, update, move, archive, delete or a
+// move between boards.
+func ParseWebhookAction(body []byte) (event CardEvent, ok bool, err error) {
+	var payload webhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return CardEvent{}, false, fmt.Errorf("error decoding webhook payload: %w", err)
+	}
+	action := payload.Action
+	card := action.Data.Card
+	if card == nil || card.ID == "" {
+		return CardEvent{}, false, nil
+	}
+
+	event = CardEvent{
+		ActionID: action.ID,
+		Date:     action.Date,
+		CardID:   card.ID,
+		Name:     card.Name,
+		Desc:     card.Desc,
+		Due:      card.Due,
+		Closed:   card.Closed,
+	}
+	if action.Data.Board != nil {
+		event.BoardID = action.Data.Board.ID
+	}
+	if action.Data.List != nil {
+		event.ListID = action.Data.List.ID
+	}
+	if card.IDList != "" {
+		event.ListID = card.IDList
+	}
+	for attribute := range action.Data.Old {
+		event.Changed = append(event.Changed, attribute)
+	}
+	sort.Strings(event.Changed)
+
+	switch action.Type {
+	case "createCard", "convertToCardFromCheckItem":
+		event.Type = CardCreated
+	case "copyCard", "moveCardToBoard":
+		event.Type = CardAdded
+	case "deleteCard":
+		event.Type = CardDeleted
+	case "moveCardFromBoard":
+		event.Type = CardRemoved
+	case "updateCard":
+		_, closedChanged := action.Data.Old["closed"]
+		switch {
+		case action.Data.ListAfter != nil:
+			event.Type = CardMoved
+			event.ListID = action.Data.ListAfter.ID
+		case closedChanged && card.Closed:
+			event.Type = CardArchived
+		case closedChanged:
+			event.Type = CardRestored
+		default:
+			event.Type = CardUpdated
+		}
+	default:
+		return CardEvent{}, false, nil
+	}
+
+	return event, true, nil
+}
+
+// RegisterWebhook makes Trello call callbackURL with the actions of a board,
+// reusing the webhook when it's already registered
+func (c Client) RegisterWebhook(boardID, callbackURL, description string) (*trello.Webhook, error) {
+	var webhooks []*trello.Webhook
+	if err := c.API.Get(fmt.Sprintf("tokens/%s/webhooks", c.API.Token), trello.Defaults(), &webhooks); err != nil {
+		return nil, fmt.Errorf("error fetching webhooks: %w", err)
+	}
+	for _, webhook := range webhooks {
+		if webhook.IDModel == boardID && webhook.CallbackURL == callbackURL {
+			webhook.SetClient(c.API)
+			return webhook, nil
+		}
+	}
+
+	webhook := &trello.Webhook{IDModel: boardID, CallbackURL: callbackURL, Description: description}
+	if err := c.API.CreateWebhook(webhook); err != nil {
+		return nil, fmt.Errorf("error registering webhook for board %s: %w", boardID, err)
+	}
+	return webhook, nil
+}
+
+/* ╭──────────────────────────────────────────╮ */
+/* │              AUX FUNCTIONS               │ */
+/* ╰──────────────────────────────────────────╯ */
+type webhookPayload struct {
+	Action struct {
+		ID   string    `json:"id"`
+		Type string    `json:"type"`
+		Date time.Time `json:"date"`
+		Data struct {
+			Card       *webhookCard               `json:"card"`
+			Board      *webhookModel              `json:"board"`
+			List       *webhookModel              `json:"list"`
+			ListBefore *webhookModel              `json:"listBefore"`
+			ListAfter  *webhookModel              `json:"listAfter"`
+			Old        map[string]json.RawMessage `json:"old"`
+		} `json:"data"`
+	} `json:"action"`
+}
+
+type webhookCard struct {
+	ID     string     `json:"id"`
+	Name   string     `json:"name"`
+	Desc   string     `json:"desc"`
+	IDList string     `json:"idList"`
+	Due    *time.Time `json:"due"`
+	Closed bool       `json:"closed"`
+}
+
+type webhookModel struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func webhookDigest(secret, callbackURL string, body []byte) []byte {
+	mac := hmac.New(sha1.New, []byte(secret))
+	mac.Write(body)
+	mac.Write([]byte(callbackURL))
+	return mac.Sum(nil)
+}
+
+func (h *WebhookHandler) log() logrus.FieldLogger {
+	if h.Logger == nil {
+		return logrus.StandardLogger()
+	}
+	return h.Logger
+}
@@ -0,0 +1,226 @@
+package trello_test
+
+import (
+	"bytes"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"txeo-tools-library/db"
+	ttrello "txeo-tools-library/trello"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	webhookSecret   = "test-app-secret"
+	webhookCallback = "https://txeo.example.com/trello/webhook"
+	webhookCardID   = "6720a1b2c3d4e5f6010203c9"
+)
+
+func newWebhookServer(t *testing.T) (*gin.Engine, *db.TrelloCardStore) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	database, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("error opening database: %v", err)
+	}
+	database.SetMaxOpenConns(1)
+	t.Cleanup(func() { database.Close() })
+
+	store, err := db.NewTrelloCardStore(database)
+	if err != nil {
+		t.Fatalf("error creating store: %v", err)
+	}
+
+	router := gin.New()
+	ttrello.NewWebhookHandler(webhookSecret, webhookCallback, store).Register(router, "/trello/webhook")
+	return router, store
+}
+
+func postWebhook(t *testing.T, router *gin.Engine, payload string, signature string) int {
+	t.Helper()
+	body, err := os.ReadFile(filepath.Join("testdata", "webhooks", payload))
+	if err != nil {
+		t.Fatalf("error reading payload: %v", err)
+	}
+	if signature == "" {
+		signature = ttrello.SignWebhook(webhookSecret, webhookCallback, body)
+	}
+
+	request := httptest.NewRequest(http.MethodPost, "/trello/webhook", bytes.NewReader(body))
+	request.Header.Set(ttrello.WebhookSignatureHeader, signature)
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	return response.Code
+}
+
+func TestWebhookHeadCheck(t *testing.T) {
+	router, _ := newWebhookServer(t)
+
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest(http.MethodHead, "/trello/webhook", nil))
+	if response.Code != http.StatusOK {
+		t.Fatalf("HEAD returned %d, want 200", response.Code)
+	}
+}
+
+func TestWebhookRejectsInvalidSignature(t *testing.T) {
+	router, store := newWebhookServer(t)
+
+	if code := postWebhook(t, router, "create_card.json", "bm90IHRoZSBzaWduYXR1cmU="); code != http.StatusUnauthorized {
+		t.Fatalf("got %d, want 401", code)
+	}
+	if _, err := store.GetCard(webhookCardID); err != sql.ErrNoRows {
+		t.Fatalf("card stored from an unsigned payload (err %v)", err)
+	}
+}
+
+func TestWebhookRejectsEverythingWithoutSecret(t *testing.T) {
+	_, store := newWebhookServer(t)
+	unsecured := gin.New()
+	ttrello.NewWebhookHandler("", webhookCallback, store).Register(unsecured, "/trello/webhook")
+
+	// Signed with an empty secret, as anybody could
+	if code := postWebhook(t, unsecured, "create_card.json", ttrello.SignWebhook("", webhookCallback, nil)); code != http.StatusServiceUnavailable {
+		t.Fatalf("got %d, want 503", code)
+	}
+	if code := postWebhook(t, unsecured, "create_card.json", "bm90IHRoZSBzaWduYXR1cmU="); code != http.StatusServiceUnavailable {
+		t.Fatalf("got %d, want 503", code)
+	}
+	if _, err := store.GetCard(webhookCardID); err != sql.ErrNoRows {
+		t.Fatalf("card stored by a handler without secret (err %v)", err)
+	}
+}
+
+func TestWebhookAppliesRecordedActions(t *testing.T) {
+	router, store := newWebhookServer(t)
+
+	steps := []struct {
+		payload  string
+		listID   string
+		task     string
+		category string
+		hours    float64
+		closed   bool
+	}{
+		{"create_card.json", "6710000000000000000000a2", "Weekly call with LIV Golf", "Catchups / Meetings", 1, false},
+		{"rename_card.json", "6710000000000000000000a2", "Implementation of the LIV Golf screensets", "Implementation / Configuration tasks", 2.5, false},
+		{"comment_card.json", "6710000000000000000000a2", "Implementation of the LIV Golf screensets", "Implementation / Configuration tasks", 2.5, false},
+		{"move_card.json", "6710000000000000000000a3", "Implementation of the LIV Golf screensets", "Implementation / Configuration tasks", 2.5, false},
+		{"archive_card.json", "6710000000000000000000a3", "Implementation of the LIV Golf screensets", "Implementation / Configuration tasks", 2.5, true},
+		// Delivered late, older than the card state
+		{"create_card.json", "6710000000000000000000a3", "Implementation of the LIV Golf screensets", "Implementation / Configuration tasks", 2.5, true},
+	}
+
+	for _, step := range steps {
+		if code := postWebhook(t, router, step.payload, ""); code != http.StatusOK {
+			t.Fatalf("%s: got %d, want 200", step.payload, code)
+		}
+		card, err := store.GetCard(webhookCardID)
+		if err != nil {
+			t.Fatalf("%s: error reading card: %v", step.payload, err)
+		}
+		if card.ListID != step.listID || card.Task.Name != step.task || card.Task.Category != step.category ||
+			card.Task.TimeForTask != step.hours || card.Closed != step.closed {
+			t.Errorf("%s: got list %s, task %q (%s, %.2fh), closed %v; want list %s, task %q (%s, %.2fh), closed %v",
+				step.payload, card.ListID, card.Task.Name, card.Task.Category, card.Task.TimeForTask, card.Closed,
+				step.listID, step.task, step.category, step.hours, step.closed)
+		}
+	}
+
+	cards, err := store.GetCards("6710000000000000000000a3")
+	if err != nil {
+		t.Fatalf("error listing cards: %v", err)
+	}
+	if len(cards) != 0 {
+		t.Errorf("archived card still listed: %+v", cards)
+	}
+}
+
+func TestWebhookKeepsHoursNotInTheAction(t *testing.T) {
+	router, store := newWebhookServer(t)
+	const cardID = "6720a1b2c3d4e5f6010203d1"
+
+	steps := []struct {
+		payload string
+		listID  string
+		hours   float64
+	}{
+		{"create_card_desc.json", "6710000000000000000000a2", 0},
+		{"update_desc_card.json", "6710000000000000000000a2", 2},  // Hours only in the description
+		{"comment_desc_card.json", "6710000000000000000000a2", 2}, // Comments don't send the description
+		{"move_desc_card.json", "6710000000000000000000a4", 2},    // Neither do moves
+	}
+	for _, step := range steps {
+		if code := postWebhook(t, router, step.payload, ""); code != http.StatusOK {
+			t.Fatalf("%s: got %d, want 200", step.payload, code)
+		}
+		card, err := store.GetCard(cardID)
+		if err != nil {
+			t.Fatalf("%s: error reading card: %v", step.payload, err)
+		}
+		if card.ListID != step.listID || card.Task.TimeForTask != step.hours {
+			t.Errorf("%s: got list %s, %.2fh; want list %s, %.2fh", step.payload, card.ListID, card.Task.TimeForTask, step.listID, step.hours)
+		}
+	}
+
+	// Hours written by the sync in the custom field win over the description
+	if _, err := store.DB.Exec(`INSERT INTO trello_card_fields (card_id, field_id, name, type, number) VALUES (?, ?, ?, ?, ?)`,
+		cardID, "6710000000000000000000f1", "Horas", ttrello.FieldTypeNumber, 3.5); err != nil {
+		t.Fatalf("error saving custom field: %v", err)
+	}
+	if code := postWebhook(t, router, "rename_desc_card.json", ""); code != http.StatusOK {
+		t.Fatalf("rename: got %d, want 200", code)
+	}
+	card, err := store.GetCard(cardID)
+	if err != nil {
+		t.Fatalf("error reading card: %v", err)
+	}
+	if card.Task.Name != "Screensets review with the client" || card.Task.TimeForTask != 3.5 {
+		t.Errorf("after rename got task %q (%.2fh), want the new name with the 3.50h of the field", card.Task.Name, card.Task.TimeForTask)
+	}
+}
+
+func TestWebhookCardMovedToAnotherBoard(t *testing.T) {
+	tests := []struct {
+		name     string
+		payloads []string
+		boardID  string
+		listID   string
+		deleted  bool
+	}{
+		{"target board without webhook", []string{"create_card.json", "move_from_board.json"}, "617c56690fcb27430e740522", "6710000000000000000000a2", true},
+		{"both boards", []string{"create_card.json", "move_from_board.json", "move_to_board.json"}, "66757258661e38a299a7e687", "6757000000000000000000a1", false},
+		{"target board first", []string{"create_card.json", "move_to_board.json", "move_from_board.json"}, "66757258661e38a299a7e687", "6757000000000000000000a1", false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router, store := newWebhookServer(t)
+			for _, payload := range test.payloads {
+				if code := postWebhook(t, router, payload, ""); code != http.StatusOK {
+					t.Fatalf("%s: got %d, want 200", payload, code)
+				}
+			}
+			card, err := store.GetCard(webhookCardID)
+			if err != nil {
+				t.Fatalf("error reading card: %v", err)
+			}
+			if card.BoardID != test.boardID || card.ListID != test.listID || card.Deleted != test.deleted {
+				t.Errorf("got board %s, list %s, deleted %v; want board %s, list %s, deleted %v",
+					card.BoardID, card.ListID, card.Deleted, test.boardID, test.listID, test.deleted)
+			}
+			cards, err := store.GetCards("6710000000000000000000a2")
+			if err != nil {
+				t.Fatalf("error listing cards: %v", err)
+			}
+			if len(cards) != 0 {
+				t.Errorf("moved card still listed in the old list: %+v", cards)
+			}
+		})
+	}
+}